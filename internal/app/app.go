@@ -10,13 +10,17 @@ import (
 	"time"
 
 	"github.com/Be1chenok/levelZero/internal/config"
+	appSubscriber "github.com/Be1chenok/levelZero/internal/delivery/broker/subscriber"
 	appHandler "github.com/Be1chenok/levelZero/internal/delivery/http/handler"
 	appServer "github.com/Be1chenok/levelZero/internal/delivery/http/server"
 	appRepository "github.com/Be1chenok/levelZero/internal/repository"
 	appBroker "github.com/Be1chenok/levelZero/internal/repository/broker"
+	"github.com/Be1chenok/levelZero/internal/repository/cache"
 	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+	appRedis "github.com/Be1chenok/levelZero/internal/repository/redis"
 	appService "github.com/Be1chenok/levelZero/internal/service"
 	appLogger "github.com/Be1chenok/levelZero/logger"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -48,23 +52,50 @@ func Run() {
 		appLog.Fatalf("failed to connect nats-streaming server: %v", err)
 	}
 
-	repository := appRepository.New(conf, logger, postgres, broker)
-	service := appService.New(repository, logger)
+	var redisClient *redis.Client
+	if conf.Cache.Backend == cache.BackendRedis || conf.Cache.Backend == cache.BackendTiered {
+		redisClient, err = appRedis.New(conf, ctx)
+		if err != nil {
+			appLog.Fatalf("failed to connect redis: %v", err)
+		}
+	}
+
+	repository, err := appRepository.New(conf, logger, postgres, redisClient, broker)
+	if err != nil {
+		appLog.Fatalf("failed to initialize repository: %v", err)
+	}
+	service := appService.New(conf, repository, logger)
+	if conf.Rates.Path != "" {
+		if _, err := service.Rate.ImportFile(ctx, conf.Rates.Path); err != nil {
+			appLog.Fatalf("failed to import exchange rates: %v", err)
+		}
+	}
 	handler := appHandler.New(conf, service)
+	subscriber := appSubscriber.New(conf, logger, broker, service)
 	server := appServer.New(conf, handler.InitRoutes())
 
-	if err := repository.CacheOrder.LoadToCache(ctx); err != nil {
-		log.Fatalf("failed to load cache: %v", err)
-	}
 	cancel()
 
 	wg := sync.WaitGroup{}
 	ctx, cancel = context.WithCancel(context.Background())
 
-	if err := repository.Broker.Subscribe(&wg, ctx); err != nil {
+	if err := subscriber.Subscribe(&wg, ctx); err != nil {
 		appLog.Fatalf("failed to subscribe to channel")
 	}
 
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := repository.CacheOrder.WarmUp(ctx); err != nil {
+			appLog.Fatalf("failed to load cache: %v", err)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		repository.CacheOrder.RunSnapshots(ctx)
+	}()
+
 	go func() {
 		if err := server.Start(); err != nil {
 			appLog.Fatalf("failed to start server: %v", err)
@@ -81,7 +112,7 @@ func Run() {
 
 	appLog.Info("shuthing down")
 
-	if err := repository.Broker.UnSubscribe(); err != nil {
+	if err := subscriber.UnSubscribe(); err != nil {
 		appLog.Fatalf("failed to unsubscribe channel: %v", err)
 	}
 
@@ -96,4 +127,10 @@ func Run() {
 	if err := postgres.Close(); err != nil {
 		appLog.Fatalf("failed to close database connection: %v", err)
 	}
+
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			appLog.Fatalf("failed to close redis connection: %v", err)
+		}
+	}
 }
@@ -10,12 +10,17 @@ type Config struct {
 	Server   ServerConfig
 	Postgres PostgresConfig
 	Stan     StanConfig
+	Order    OrderConfig
+	Cache    CacheConfig
+	Redis    RedisConfig
+	Rates    RatesConfig
 }
 
 type ServerConfig struct {
 	Host        string
 	Port        int
 	RequestTime time.Duration
+	AdminToken  string
 }
 
 type PostgresConfig struct {
@@ -28,13 +33,53 @@ type PostgresConfig struct {
 }
 
 type StanConfig struct {
-	Host        string
-	Port        int
-	ClusterID   string
-	ClientID    string
-	Subject     string
-	AckWait     time.Duration
-	DurableName string
+	Host          string
+	Port          int
+	ClusterID     string
+	ClientID      string
+	Subject       string
+	AckWait       time.Duration
+	DurableName   string
+	DLQSubject    string
+	EventsSubject string
+	Retry         RetryConfig
+}
+
+type RetryConfig struct {
+	Attempts        int
+	Backoff         time.Duration
+	MaxBackoff      time.Duration
+	MaxRedeliveries int
+}
+
+type OrderConfig struct {
+	ConflictPolicy string
+}
+
+type CacheConfig struct {
+	Backend          string
+	LoadBatchSize    int
+	MaxEntries       int
+	MaxBytes         int64
+	Policy           string
+	TTL              time.Duration
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+	WarmUpFailure    string
+	WarmUpBackoff    time.Duration
+}
+
+type RatesConfig struct {
+	Path string
+}
+
+type RedisConfig struct {
+	Host      string
+	Port      int
+	Password  string
+	DB        int
+	KeyPrefix string
+	Timeout   time.Duration
 }
 
 func Init() (*Config, error) {
@@ -49,6 +94,7 @@ func Init() (*Config, error) {
 				Host:        viper.GetString("SERVER_HOST"),
 				Port:        viper.GetInt("SERVER_PORT"),
 				RequestTime: viper.GetDuration("REQUEST_TIME") * time.Second,
+				AdminToken:  viper.GetString("ADMIN_TOKEN"),
 			},
 			PostgresConfig{
 				Host:     viper.GetString("PG_HOST"),
@@ -59,13 +105,47 @@ func Init() (*Config, error) {
 				SSLMode:  viper.GetString("PG_SSL_MODE"),
 			},
 			StanConfig{
-				Host:        viper.GetString("NATS_HOST"),
-				Port:        viper.GetInt("NATS_PORT"),
-				ClusterID:   viper.GetString("NATS_CLUSTER_ID"),
-				ClientID:    viper.GetString("NATS_CLIENT_ID"),
-				Subject:     viper.GetString("NATS_SUBJECT"),
-				AckWait:     viper.GetDuration("NATS_ACK_WAIT") * time.Second,
-				DurableName: viper.GetString("NATS_DURABLE_NAME"),
+				Host:          viper.GetString("NATS_HOST"),
+				Port:          viper.GetInt("NATS_PORT"),
+				ClusterID:     viper.GetString("NATS_CLUSTER_ID"),
+				ClientID:      viper.GetString("NATS_CLIENT_ID"),
+				Subject:       viper.GetString("NATS_SUBJECT"),
+				AckWait:       viper.GetDuration("NATS_ACK_WAIT") * time.Second,
+				DurableName:   viper.GetString("NATS_DURABLE_NAME"),
+				DLQSubject:    viper.GetString("NATS_DLQ_SUBJECT"),
+				EventsSubject: viper.GetString("NATS_EVENTS_SUBJECT"),
+				Retry: RetryConfig{
+					Attempts:        viper.GetInt("NATS_RETRY_ATTEMPTS"),
+					Backoff:         viper.GetDuration("NATS_RETRY_BACKOFF") * time.Millisecond,
+					MaxBackoff:      viper.GetDuration("NATS_RETRY_MAX_BACKOFF") * time.Millisecond,
+					MaxRedeliveries: viper.GetInt("NATS_MAX_REDELIVERIES"),
+				},
+			},
+			OrderConfig{
+				ConflictPolicy: viper.GetString("ORDER_CONFLICT_POLICY"),
+			},
+			CacheConfig{
+				Backend:          viper.GetString("CACHE_BACKEND"),
+				LoadBatchSize:    viper.GetInt("CACHE_LOAD_BATCH_SIZE"),
+				MaxEntries:       viper.GetInt("CACHE_MAX_ENTRIES"),
+				MaxBytes:         viper.GetInt64("CACHE_MAX_BYTES"),
+				Policy:           viper.GetString("CACHE_POLICY"),
+				TTL:              viper.GetDuration("CACHE_TTL") * time.Second,
+				SnapshotPath:     viper.GetString("CACHE_SNAPSHOT_PATH"),
+				SnapshotInterval: viper.GetDuration("CACHE_SNAPSHOT_INTERVAL") * time.Second,
+				WarmUpFailure:    viper.GetString("CACHE_WARMUP_FAILURE"),
+				WarmUpBackoff:    viper.GetDuration("CACHE_WARMUP_BACKOFF") * time.Second,
+			},
+			RedisConfig{
+				Host:      viper.GetString("REDIS_HOST"),
+				Port:      viper.GetInt("REDIS_PORT"),
+				Password:  viper.GetString("REDIS_PASS"),
+				DB:        viper.GetInt("REDIS_DB"),
+				KeyPrefix: viper.GetString("REDIS_KEY_PREFIX"),
+				Timeout:   viper.GetDuration("REDIS_TIMEOUT") * time.Millisecond,
+			},
+			RatesConfig{
+				Path: viper.GetString("RATES_PATH"),
 			},
 		},
 		nil
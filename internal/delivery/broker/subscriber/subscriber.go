@@ -0,0 +1,228 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	appService "github.com/Be1chenok/levelZero/internal/service"
+	appLogger "github.com/Be1chenok/levelZero/logger"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/stan.go"
+	"go.uber.org/zap"
+)
+
+type Subscriber interface {
+	Subscribe(wg *sync.WaitGroup, ctx context.Context) error
+	UnSubscribe() error
+}
+
+type subscriber struct {
+	conf    *config.Config
+	logger  appLogger.Logger
+	sub     stan.Subscription
+	events  *nats.Subscription
+	sc      stan.Conn
+	service *appService.Service
+}
+
+func New(conf *config.Config, logger appLogger.Logger, sc stan.Conn, service *appService.Service) Subscriber {
+	return &subscriber{
+		conf:    conf,
+		sc:      sc,
+		service: service,
+		logger:  logger.With(zap.String("component", "subscriber")),
+	}
+}
+
+func (s *subscriber) Subscribe(wg *sync.WaitGroup, ctx context.Context) error {
+	var err error
+	s.sub, err = s.sc.Subscribe(s.conf.Stan.Subject, func(msg *stan.Msg) {
+		wg.Add(1)
+		defer wg.Done()
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			s.processMessage(ctx, msg)
+		}
+	},
+		stan.AckWait(s.conf.Stan.AckWait),
+		stan.DurableName(s.conf.Stan.DurableName),
+		stan.SetManualAckMode(),
+		stan.MaxInflight(15))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	if s.conf.Stan.EventsSubject != "" {
+		s.events, err = s.sc.NatsConn().Subscribe(s.conf.Stan.EventsSubject, s.handleEvent)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to events: %w", err)
+		}
+	}
+
+	s.logger.Infof("subscribe succesful")
+
+	return nil
+}
+
+func (s *subscriber) UnSubscribe() error {
+	if s.sub != nil {
+		if err := s.sub.Unsubscribe(); err != nil {
+			return fmt.Errorf("failed to unsubscribe: %w", err)
+		}
+	}
+
+	if s.events != nil {
+		if err := s.events.Unsubscribe(); err != nil {
+			return fmt.Errorf("failed to unsubscribe from events: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// handleEvent drops the order changed by another instance from the local cache,
+// the events published by this instance are skipped.
+func (s *subscriber) handleEvent(msg *nats.Msg) {
+	var event domain.OrderChanged
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.Errorf("failed to unmarshal event: %v", err)
+		return
+	}
+
+	if event.Instance == s.conf.Stan.ClientID {
+		return
+	}
+
+	if err := s.service.Cache.Invalidate(event.OrderUID); err != nil {
+		s.logger.Errorf("failed to handle order %s change: %v", event.OrderUID, err)
+	}
+}
+
+func (s subscriber) processMessage(ctx context.Context, msg *stan.Msg) {
+	if !s.handleMessage(ctx, msg) {
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		s.logger.Infof("failed to acknowledge message: %v\n", err)
+	}
+}
+
+// handleMessage reports whether the message may be acknowledged, that is whether
+// it was handled or dead-lettered. Messages that failed with a temporary error are
+// left unacknowledged, so the server redelivers them after AckWait, until
+// MaxRedeliveries is exceeded.
+func (s subscriber) handleMessage(ctx context.Context, msg *stan.Msg) bool {
+	if msg.Redelivered {
+		s.logger.Infof("message %d redelivered %d times", msg.Sequence, msg.RedeliveryCount)
+	} else {
+		s.logger.Info("message received")
+	}
+
+	ctx = domain.WithSource(ctx, domain.Source{
+		Channel:   domain.ChannelBroker,
+		Actor:     msg.Subject,
+		Reference: strconv.FormatUint(msg.Sequence, 10),
+	})
+
+	err := s.handleWithRetry(ctx, msg.Data)
+	if err != nil {
+		s.logger.Errorf("failed to handle message %d: %v", msg.Sequence, err)
+
+		if errors.Is(err, domain.ErrTemporary) {
+			if int(msg.RedeliveryCount) < s.conf.Stan.Retry.MaxRedeliveries {
+				s.logger.Infof("message %d will be redelivered", msg.Sequence)
+				return false
+			}
+			err = fmt.Errorf("gave up after %d redeliveries: %w", msg.RedeliveryCount, err)
+		}
+
+		if err := s.deadLetter(ctx, msg, err); err != nil {
+			s.logger.Errorf("failed to dead-letter message %d: %v", msg.Sequence, err)
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s subscriber) handleWithRetry(ctx context.Context, data []byte) error {
+	backoff := s.conf.Stan.Retry.Backoff
+
+	for attempt := 1; ; attempt++ {
+		err := s.messageHandler(data, ctx)
+		if err == nil || !errors.Is(err, domain.ErrTemporary) || attempt >= s.conf.Stan.Retry.Attempts {
+			return err
+		}
+
+		s.logger.Warnf("attempt %d failed, retrying in %v: %v", attempt, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.conf.Stan.Retry.MaxBackoff {
+			backoff = s.conf.Stan.Retry.MaxBackoff
+		}
+	}
+}
+
+func (s subscriber) messageHandler(data []byte, ctx context.Context) error {
+	command, err := domain.ParseCommand(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse command: %w", err)
+	}
+
+	_, outcome, err := s.service.Order.Execute(ctx, command)
+	if err != nil {
+		return fmt.Errorf("failed to %s order: %w", command.Type, err)
+	}
+	s.logger.Infof("order %s handled: %s", command.OrderUID, outcome)
+
+	return nil
+}
+
+// deadLetter stores the failed message and publishes it to the dead-letter subject
+// when one is configured. The message may be acknowledged if either of them succeeds.
+func (s subscriber) deadLetter(ctx context.Context, msg *stan.Msg, reason error) error {
+	now := time.Now().UTC()
+
+	_, storeErr := s.service.DeadLetter.Add(ctx, domain.DeadLetter{
+		Subject:       msg.Subject,
+		Sequence:      msg.Sequence,
+		Payload:       string(msg.Data),
+		Error:         reason.Error(),
+		Attempts:      int(msg.RedeliveryCount) + 1,
+		FirstFailedAt: now,
+		LastFailedAt:  now,
+	})
+	if storeErr != nil {
+		s.logger.Errorf("failed to store dead letter: %v", storeErr)
+	}
+
+	if s.conf.Stan.DLQSubject == "" {
+		return storeErr
+	}
+
+	if err := s.sc.Publish(s.conf.Stan.DLQSubject, msg.Data); err != nil {
+		s.logger.Errorf("failed to publish message to %s: %v", s.conf.Stan.DLQSubject, err)
+		if storeErr != nil {
+			return fmt.Errorf("failed to store and publish dead letter: %w", storeErr)
+		}
+	}
+
+	return nil
+}
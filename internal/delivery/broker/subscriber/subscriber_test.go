@@ -0,0 +1,143 @@
+package subscriber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	appService "github.com/Be1chenok/levelZero/internal/service"
+	"github.com/nats-io/stan.go"
+	"github.com/nats-io/stan.go/pb"
+	"go.uber.org/zap"
+)
+
+type fakeOrder struct {
+	appService.Order
+	err   error
+	calls int
+}
+
+func (o *fakeOrder) Execute(ctx context.Context, command domain.Command) (domain.Order, domain.Outcome, error) {
+	o.calls++
+	return domain.Order{}, domain.OutcomeCreated, o.err
+}
+
+type fakeDeadLetter struct {
+	appService.DeadLetter
+	err   error
+	added []domain.DeadLetter
+}
+
+func (d *fakeDeadLetter) Add(ctx context.Context, deadLetter domain.DeadLetter) (int64, error) {
+	d.added = append(d.added, deadLetter)
+	return int64(len(d.added)), d.err
+}
+
+func TestHandleMessage(t *testing.T) {
+	const payload = `{"type": "create", "order": {"order_uid": "b563feb7b2b84b6test"}}`
+
+	tests := []struct {
+		name            string
+		data            string
+		redeliveries    uint32
+		orderErr        error
+		deadLetterErr   error
+		wantAck         bool
+		wantCalls       int
+		wantDeadLetters int
+	}{
+		{name: "handled", data: payload, wantAck: true, wantCalls: 1},
+		{
+			name:      "temporary error",
+			data:      payload,
+			orderErr:  fmt.Errorf("failed to save order: %w", domain.ErrTemporary),
+			wantAck:   false,
+			wantCalls: 2,
+		},
+		{
+			name:            "temporary error after the last redelivery",
+			data:            payload,
+			redeliveries:    3,
+			orderErr:        fmt.Errorf("failed to save order: %w", domain.ErrTemporary),
+			wantAck:         true,
+			wantCalls:       2,
+			wantDeadLetters: 1,
+		},
+		{
+			name:            "validation error",
+			data:            payload,
+			orderErr:        &domain.ValidationError{Violations: []domain.Violation{{Field: "order_uid", Rule: domain.RuleRequired}}},
+			wantAck:         true,
+			wantCalls:       1,
+			wantDeadLetters: 1,
+		},
+		{
+			name:            "permanent error",
+			data:            payload,
+			orderErr:        domain.ErrVersionConflict,
+			wantAck:         true,
+			wantCalls:       1,
+			wantDeadLetters: 1,
+		},
+		{
+			name:            "invalid payload",
+			data:            `{"type": "create"`,
+			wantAck:         true,
+			wantDeadLetters: 1,
+		},
+		{
+			name:            "dead letter not stored",
+			data:            payload,
+			orderErr:        domain.ErrVersionConflict,
+			deadLetterErr:   errors.New("connection refused"),
+			wantAck:         false,
+			wantCalls:       1,
+			wantDeadLetters: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &fakeOrder{err: tt.orderErr}
+			deadLetter := &fakeDeadLetter{err: tt.deadLetterErr}
+
+			conf := &config.Config{}
+			conf.Stan.Retry = config.RetryConfig{
+				Attempts:        2,
+				Backoff:         time.Millisecond,
+				MaxBackoff:      time.Millisecond,
+				MaxRedeliveries: 3,
+			}
+			s := subscriber{
+				conf:    conf,
+				logger:  zap.NewNop().Sugar(),
+				service: &appService.Service{Order: order, DeadLetter: deadLetter},
+			}
+
+			msg := &stan.Msg{MsgProto: pb.MsgProto{
+				Sequence:        7,
+				Subject:         "orders",
+				Data:            []byte(tt.data),
+				Redelivered:     tt.redeliveries > 0,
+				RedeliveryCount: tt.redeliveries,
+			}}
+
+			if ack := s.handleMessage(context.Background(), msg); ack != tt.wantAck {
+				t.Errorf("handleMessage() = %v, want %v", ack, tt.wantAck)
+			}
+			if order.calls != tt.wantCalls {
+				t.Errorf("Execute() called %d times, want %d", order.calls, tt.wantCalls)
+			}
+			if len(deadLetter.added) != tt.wantDeadLetters {
+				t.Fatalf("dead letters = %d, want %d", len(deadLetter.added), tt.wantDeadLetters)
+			}
+			if tt.wantDeadLetters > 0 && deadLetter.added[0].Sequence != msg.Sequence {
+				t.Errorf("dead letter sequence = %d, want %d", deadLetter.added[0].Sequence, msg.Sequence)
+			}
+		})
+	}
+}
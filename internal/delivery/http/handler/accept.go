@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type mediaRange struct {
+	mainType string
+	subType  string
+	quality  float64
+}
+
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(strings.TrimSpace(part), ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		mainType, subType, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(key) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+
+		ranges = append(ranges, mediaRange{
+			mainType: mainType,
+			subType:  subType,
+			quality:  quality,
+		})
+	}
+
+	return ranges
+}
+
+func (m mediaRange) match(offer string) (int, bool) {
+	mainType, subType, _ := strings.Cut(offer, "/")
+
+	switch {
+	case m.mainType == mainType && m.subType == subType:
+		return 3, true
+	case m.mainType == mainType && m.subType == "*":
+		return 2, true
+	case m.mainType == "*" && m.subType == "*":
+		return 1, true
+	}
+
+	return 0, false
+}
+
+// negotiate returns the offer that best matches the Accept header of the request
+// or an empty string when none of the offers is acceptable. The first offer wins
+// when the header is missing or several offers have the same quality.
+func negotiate(r *http.Request, offers ...string) string {
+	header := r.Header.Get(accept)
+	if header == "" {
+		return offers[0]
+	}
+
+	best := ""
+	bestQuality := 0.0
+
+	for _, offer := range offers {
+		quality := 0.0
+		specificity := 0
+
+		for _, m := range parseAccept(header) {
+			s, ok := m.match(offer)
+			if ok && s > specificity {
+				specificity = s
+				quality = m.quality
+			}
+		}
+
+		if quality > bestQuality {
+			best = offer
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		offers []string
+		want   string
+	}{
+		{name: "missing header", offers: []string{textHtml, applicationJson}, want: textHtml},
+		{name: "exact match", accept: applicationJson, offers: []string{textHtml, applicationJson}, want: applicationJson},
+		{name: "browser", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", offers: []string{textHtml, applicationJson}, want: textHtml},
+		{name: "any", accept: "*/*", offers: []string{textHtml, applicationJson}, want: textHtml},
+		{name: "quality", accept: "text/html;q=0.5, application/json", offers: []string{textHtml, applicationJson}, want: applicationJson},
+		{name: "same quality keeps offer order", accept: "application/json, text/html", offers: []string{textHtml, applicationJson}, want: textHtml},
+		{name: "subtype wildcard", accept: "application/*", offers: []string{textHtml, applicationJson}, want: applicationJson},
+		{name: "specific range overrides wildcard", accept: "*/*;q=0.9, text/html;q=0.1", offers: []string{textHtml, applicationJson}, want: applicationJson},
+		{name: "case insensitive", accept: "Application/JSON", offers: []string{textHtml, applicationJson}, want: applicationJson},
+		{name: "rejected with zero quality", accept: "application/json;q=0", offers: []string{applicationJson}, want: ""},
+		{name: "not acceptable", accept: "image/png", offers: []string{textHtml, applicationJson}, want: ""},
+		{name: "malformed ranges are skipped", accept: "json, , text/html", offers: []string{applicationJson, textHtml}, want: textHtml},
+		{name: "invalid quality counts as 1", accept: "text/html;q=abc, application/json;q=0.5", offers: []string{applicationJson, textHtml}, want: textHtml},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set(accept, tt.accept)
+			}
+
+			if got := negotiate(r, tt.offers...); got != tt.want {
+				t.Errorf("negotiate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
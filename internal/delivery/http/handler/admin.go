@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/gorilla/mux"
+)
+
+const (
+	authorization = "Authorization"
+	bearerPrefix  = "Bearer "
+)
+
+type evictResponse struct {
+	Evicted int `json:"evicted"`
+}
+
+// requireAdmin lets through requests bearing ADMIN_TOKEN, admin endpoints
+// are disabled when the token is not configured.
+func (h Handler) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.conf.Server.AdminToken == "" {
+			writeJsonErrorResponse(w, http.StatusForbidden, ErrAdminDisabled)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get(authorization), bearerPrefix)
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.conf.Server.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJsonErrorResponse(w, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h Handler) EvictOrder(w http.ResponseWriter, r *http.Request) {
+	evicted, err := h.service.Cache.Evict(mux.Vars(r)["uid"])
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	if !evicted {
+		writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h Handler) EvictOrders(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	if prefix == "" {
+		writeJsonErrorResponse(w, http.StatusBadRequest, invalidQueryParameter("prefix"))
+		return
+	}
+
+	evicted, err := h.service.Cache.EvictPrefix(prefix)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, evictResponse{Evicted: evicted})
+}
+
+func (h Handler) FlushCache(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Cache.Flush(); err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h Handler) ReloadOrder(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	order, err := h.service.Cache.Reload(ctx, mux.Vars(r)["uid"])
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, order)
+}
+
+func (h Handler) CacheStats(w http.ResponseWriter, r *http.Request) {
+	writeJsonResponse(w, http.StatusOK, h.service.Cache.Stats())
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/gorilla/mux"
+)
+
+const defaultAnalyticsLimit = 10
+
+func (h Handler) Analytics(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	dimension := domain.AnalyticsDimension(mux.Vars(r)["dimension"])
+	if !dimension.Valid() {
+		writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+		return
+	}
+
+	query, err := parseAnalyticsQuery(r.URL.Query())
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	query.Dimension = dimension
+
+	report, err := h.service.Analytics.Report(ctx, query)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	if report == nil {
+		report = []domain.AnalyticsRow{}
+	}
+
+	writeJsonResponse(w, http.StatusOK, report)
+}
+
+func parseAnalyticsQuery(values url.Values) (domain.AnalyticsQuery, error) {
+	query := domain.AnalyticsQuery{
+		Bucket: domain.Bucket(values.Get("bucket")),
+		Limit:  defaultAnalyticsLimit,
+	}
+
+	if !query.Bucket.Valid() {
+		return domain.AnalyticsQuery{}, invalidQueryParameter("bucket")
+	}
+
+	var err error
+	if value := values.Get("from"); value != "" {
+		if query.From, err = parseDate(value, false); err != nil {
+			return domain.AnalyticsQuery{}, invalidQueryParameter("from")
+		}
+	}
+
+	if value := values.Get("to"); value != "" {
+		if query.To, err = parseDate(value, true); err != nil {
+			return domain.AnalyticsQuery{}, invalidQueryParameter("to")
+		}
+	}
+
+	if value := values.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil || query.Limit < 1 || query.Limit > maxLimit {
+			return domain.AnalyticsQuery{}, ErrInvalidLimit
+		}
+	}
+
+	return query, nil
+}
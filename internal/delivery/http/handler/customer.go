@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/gorilla/mux"
+)
+
+const (
+	customerHtml        = "../../web/template/customer.html"
+	maxCustomerIDLength = 64
+)
+
+type customerView struct {
+	domain.CustomerSummary
+	Page    domain.OrderPage
+	NextURL string
+}
+
+func (h Handler) FindCustomer(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	customerID := mux.Vars(r)["id"]
+	if len(customerID) > maxCustomerIDLength {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidCustomerID)
+		return
+	}
+
+	summary, err := h.service.Customer.Summary(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, summary)
+}
+
+// FindCustomerOrders lists the orders of the customer, it accepts the
+// pagination and the filters of FindOrders.
+func (h Handler) FindCustomerOrders(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	customerID := mux.Vars(r)["id"]
+	if len(customerID) > maxCustomerIDLength {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidCustomerID)
+		return
+	}
+
+	filter, err := parseOrderFilter(r.URL.Query())
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	filter.CustomerID = customerID
+
+	page, err := h.service.Order.FindAll(ctx, filter)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, page)
+}
+
+func (h Handler) CustomerPage(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	customerID := mux.Vars(r)["id"]
+	if len(customerID) > maxCustomerIDLength {
+		h.NothingFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter, err := parseOrderFilter(query)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	filter.CustomerID = customerID
+
+	summary, err := h.service.Customer.Summary(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			h.NothingFound(w, r)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	page, err := h.service.Order.FindAll(ctx, filter)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	view := customerView{
+		CustomerSummary: summary,
+		Page:            page,
+	}
+
+	if page.NextCursor != "" {
+		next := url.Values{}
+		for key, values := range query {
+			next[key] = values
+		}
+		next.Set("cursor", page.NextCursor)
+		view.NextURL = "/customers/" + url.PathEscape(customerID) + "?" + next.Encode()
+	}
+
+	tmpl, err := template.ParseFiles(customerHtml)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	w.Header().Set(contentType, textHtml)
+	w.WriteHeader(http.StatusOK)
+	if err = tmpl.Execute(w, view); err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+func (h Handler) FindDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	query := r.URL.Query()
+
+	filter := domain.DeadLetterFilter{
+		Pending: true,
+		Limit:   defaultLimit,
+	}
+
+	var err error
+	if value := query.Get("pending"); value != "" {
+		if filter.Pending, err = strconv.ParseBool(value); err != nil {
+			writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidQueryParameter)
+			return
+		}
+	}
+
+	if filter.Limit, filter.Offset, err = parsePagination(r); err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	deadLetters, err := h.service.DeadLetter.FindAll(ctx, filter)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	if deadLetters == nil {
+		deadLetters = []domain.DeadLetter{}
+	}
+
+	writeJsonResponse(w, http.StatusOK, deadLetters)
+}
+
+func (h Handler) FindDeadLetterByID(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidDeadLetterID)
+		return
+	}
+
+	deadLetter, err := h.service.DeadLetter.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, deadLetter)
+}
+
+func (h Handler) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidDeadLetterID)
+		return
+	}
+
+	order, outcome, err := h.service.DeadLetter.Replay(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNothingFound):
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+		case errors.Is(err, domain.ErrAlreadyReplayed):
+			writeJsonErrorResponse(w, http.StatusConflict, domain.ErrAlreadyReplayed)
+		default:
+			statusCode, err := createErrorResponse(err)
+			writeJsonErrorResponse(w, statusCode, err)
+		}
+		return
+	}
+
+	w.Header().Set(orderOutcome, string(outcome))
+	writeJsonResponse(w, outcomeStatus(outcome), order)
+}
+
+func parsePagination(r *http.Request) (int, int, error) {
+	query := r.URL.Query()
+	limit, offset := defaultLimit, 0
+
+	var err error
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxLimit {
+			return 0, 0, ErrInvalidLimit
+		}
+	}
+
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, ErrInvalidOffset
+		}
+	}
+
+	return limit, offset, nil
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	appService "github.com/Be1chenok/levelZero/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type fakeDeadLetterService struct {
+	appService.DeadLetter
+	outcome domain.Outcome
+}
+
+func (s fakeDeadLetterService) Replay(ctx context.Context, id int64) (domain.Order, domain.Outcome, error) {
+	return domain.Order{UID: "b563feb7b2b84b6test"}, s.outcome, nil
+}
+
+func TestReplayDeadLetterStatus(t *testing.T) {
+	tests := []struct {
+		outcome    domain.Outcome
+		wantStatus int
+	}{
+		{outcome: domain.OutcomeCreated, wantStatus: http.StatusCreated},
+		{outcome: domain.OutcomeUpdated, wantStatus: http.StatusOK},
+		{outcome: domain.OutcomeUnchanged, wantStatus: http.StatusOK},
+		{outcome: domain.OutcomeDeleted, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.outcome), func(t *testing.T) {
+			conf := &config.Config{}
+			conf.Server.RequestTime = time.Second
+			h := Handler{service: &appService.Service{DeadLetter: fakeDeadLetterService{outcome: tt.outcome}}, conf: conf}
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/admin/dead-letters/1/replay", nil)
+			r = mux.SetURLVars(r, map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+
+			h.ReplayDeadLetter(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get(orderOutcome); got != string(tt.outcome) {
+				t.Errorf("%s = %q, want %q", orderOutcome, got, tt.outcome)
+			}
+		})
+	}
+}
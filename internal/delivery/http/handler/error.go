@@ -3,5 +3,23 @@ package handler
 import "errors"
 
 var (
-	ErrSomethingWentWrong = errors.New("oops, something went wrong")
+	ErrSomethingWentWrong    = errors.New("oops, something went wrong")
+	ErrInvalidOrderUID       = errors.New("invalid order uid")
+	ErrNotAcceptable         = errors.New("not acceptable")
+	ErrInvalidRequestBody    = errors.New("invalid request body")
+	ErrEmptyBatch            = errors.New("batch is empty")
+	ErrBatchTooLarge         = errors.New("batch is too large")
+	ErrInvalidDeadLetterID   = errors.New("invalid dead letter id")
+	ErrInvalidQueryParameter = errors.New("invalid query parameter")
+	ErrInvalidLimit          = errors.New("invalid limit")
+	ErrInvalidOffset         = errors.New("invalid offset")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrAdminDisabled         = errors.New("admin endpoints are disabled")
+	ErrInvalidIfMatch        = errors.New("invalid If-Match header")
+	ErrPreconditionRequired  = errors.New("If-Match header is required")
+	ErrPreconditionFailed    = errors.New("order version does not match If-Match")
+	ErrOrderUIDMismatch      = errors.New("order uid does not match the path")
+	ErrUnsupportedMediaType  = errors.New("unsupported media type")
+	ErrInvalidCustomerID     = errors.New("invalid customer id")
+	ErrInvalidTrackNumber    = errors.New("invalid track number")
 )
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+const (
+	etag        = "ETag"
+	ifMatch     = "If-Match"
+	ifNoneMatch = "If-None-Match"
+)
+
+// formatETag makes the strong entity tag of the order version.
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// formatConvertedETag makes the strong entity tag of the order version converted
+// to the base currency, the effective date identifies the rate used.
+func formatConvertedETag(version int64, conversion domain.Conversion) string {
+	return `"` + strconv.FormatInt(version, 10) + "-" + conversion.Base + "-" +
+		strconv.FormatInt(conversion.EffectiveAt.Unix(), 10) + `"`
+}
+
+// parseIfMatch returns the order version required by If-Match, 0 for "*".
+// It accepts a single strong entity tag only.
+func parseIfMatch(r *http.Request) (int64, bool, error) {
+	value := strings.TrimSpace(r.Header.Get(ifMatch))
+	if value == "" {
+		return 0, false, nil
+	}
+
+	if value == "*" {
+		return 0, true, nil
+	}
+
+	if !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) || len(value) < 2 {
+		return 0, false, ErrInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, false, ErrInvalidIfMatch
+	}
+
+	return version, true, nil
+}
+
+// noneMatch reports whether If-None-Match lists the current entity tag,
+// entity tags are compared weakly.
+func noneMatch(r *http.Request, current string) bool {
+	value := r.Header.Get(ifNoneMatch)
+	if value == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		ok      bool
+		wantErr error
+	}{
+		{name: "missing"},
+		{name: "any", header: "*", ok: true},
+		{name: "version", header: `"3"`, version: 3, ok: true},
+		{name: "surrounding spaces", header: ` "12" `, version: 12, ok: true},
+		{name: "unquoted", header: "3", wantErr: ErrInvalidIfMatch},
+		{name: "weak tag", header: `W/"3"`, wantErr: ErrInvalidIfMatch},
+		{name: "list", header: `"3", "4"`, wantErr: ErrInvalidIfMatch},
+		{name: "not a number", header: `"abc"`, wantErr: ErrInvalidIfMatch},
+		{name: "zero", header: `"0"`, wantErr: ErrInvalidIfMatch},
+		{name: "negative", header: `"-1"`, wantErr: ErrInvalidIfMatch},
+		{name: "lone quote", header: `"`, wantErr: ErrInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(ifMatch, tt.header)
+			}
+
+			version, ok, err := parseIfMatch(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseIfMatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if version != tt.version || ok != tt.ok {
+				t.Errorf("parseIfMatch() = %d, %v, want %d, %v", version, ok, tt.version, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "missing", want: false},
+		{name: "same version", header: `"3"`, want: true},
+		{name: "other version", header: `"2"`, want: false},
+		{name: "weak comparison", header: `W/"3"`, want: true},
+		{name: "list", header: `"1", "3"`, want: true},
+		{name: "any", header: "*", want: true},
+		{name: "unquoted", header: "3", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(ifNoneMatch, tt.header)
+			}
+
+			if got := noneMatch(r, formatETag(3)); got != tt.want {
+				t.Errorf("noneMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatETag(t *testing.T) {
+	if got := formatETag(42); got != `"42"` {
+		t.Errorf("formatETag() = %s, want %q", got, `"42"`)
+	}
+}
+
+func TestFormatConvertedETag(t *testing.T) {
+	conversion := domain.Conversion{Base: "EUR", EffectiveAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	want := `"42-EUR-1704067200"`
+	if got := formatConvertedETag(42, conversion); got != want {
+		t.Errorf("formatConvertedETag() = %s, want %s", got, want)
+	}
+
+	tags := map[string]bool{formatETag(42): true, want: true}
+	for _, other := range []domain.Conversion{
+		{Base: "USD", EffectiveAt: conversion.EffectiveAt},
+		{Base: "EUR", EffectiveAt: conversion.EffectiveAt.AddDate(0, 0, 1)},
+	} {
+		tag := formatConvertedETag(42, other)
+		if tags[tag] {
+			t.Errorf("formatConvertedETag() = %s, the tag is not unique", tag)
+		}
+		tags[tag] = true
+	}
+}
@@ -10,8 +10,12 @@ import (
 
 const (
 	contentType     = "Content-Type"
+	accept          = "Accept"
+	vary            = "Vary"
+	orderOutcome    = "X-Order-Outcome"
 	applicationJson = "application/json"
 	textHtml        = "text/html"
+	textCsv         = "text/csv"
 )
 
 type Handler struct {
@@ -28,11 +32,49 @@ func New(conf *config.Config, service *appService.Service) *Handler {
 
 func (h Handler) InitRoutes() http.Handler {
 	router := mux.NewRouter()
+	router.Use(h.withSource)
+
+	router.HandleFunc("/health/ready", h.Ready).Methods(http.MethodGet)
 
 	router.HandleFunc("/search", h.Search)
 
 	router.HandleFunc("/order", h.HomePage)
-	router.HandleFunc("/order/{uid:[a-zA-Z0-9]+}", h.FindOrderByUID).Methods("GET")
+	router.HandleFunc("/order/{uid:[a-zA-Z0-9]+}", h.FindOrderByUID).Methods(http.MethodGet)
+	router.HandleFunc("/orders", h.OrdersPage).Methods(http.MethodGet)
+	router.HandleFunc("/customers/{id}", h.CustomerPage).Methods(http.MethodGet)
+	router.HandleFunc("/tracking/{trackNumber}", h.TrackingPage).Methods(http.MethodGet)
+
+	api := router.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/orders", h.FindOrders).Methods(http.MethodGet)
+	api.HandleFunc("/orders", h.CreateOrder).Methods(http.MethodPost)
+	api.HandleFunc("/orders/batch", h.CreateOrders).Methods(http.MethodPost)
+	api.HandleFunc("/orders/{uid}", h.GetOrder).Methods(http.MethodGet)
+	api.HandleFunc("/orders/{uid}", h.UpdateOrder).Methods(http.MethodPut)
+	api.HandleFunc("/orders/{uid}", h.DeleteOrder).Methods(http.MethodDelete)
+	api.HandleFunc("/orders/{uid}/history", h.FindOrderHistory).Methods(http.MethodGet)
+	api.HandleFunc("/orders/{uid}/transitions", h.FindOrderStatusHistory).Methods(http.MethodGet)
+	api.HandleFunc("/orders/{uid}/transitions", h.ChangeOrderStatus).Methods(http.MethodPost)
+
+	api.HandleFunc("/tracking/{trackNumber}", h.Track).Methods(http.MethodGet)
+
+	api.HandleFunc("/customers/{id}", h.FindCustomer).Methods(http.MethodGet)
+	api.HandleFunc("/customers/{id}/orders", h.FindCustomerOrders).Methods(http.MethodGet)
+
+	api.HandleFunc("/rates", h.FindRates).Methods(http.MethodGet)
+	api.HandleFunc("/reports/totals", h.Totals).Methods(http.MethodGet)
+	api.HandleFunc("/analytics/{dimension}", h.Analytics).Methods(http.MethodGet)
+
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(h.requireAdmin)
+	admin.HandleFunc("/dead-letters", h.FindDeadLetters).Methods(http.MethodGet)
+	admin.HandleFunc("/dead-letters/{id:[0-9]+}", h.FindDeadLetterByID).Methods(http.MethodGet)
+	admin.HandleFunc("/dead-letters/{id:[0-9]+}/replay", h.ReplayDeadLetter).Methods(http.MethodPost)
+	admin.HandleFunc("/cache", h.FlushCache).Methods(http.MethodDelete)
+	admin.HandleFunc("/cache/stats", h.CacheStats).Methods(http.MethodGet)
+	admin.HandleFunc("/cache/orders", h.EvictOrders).Methods(http.MethodDelete)
+	admin.HandleFunc("/cache/orders/{uid}", h.EvictOrder).Methods(http.MethodDelete)
+	admin.HandleFunc("/cache/orders/{uid}/reload", h.ReloadOrder).Methods(http.MethodPost)
+	admin.HandleFunc("/rates", h.ImportRates).Methods(http.MethodPost)
 
 	return router
 }
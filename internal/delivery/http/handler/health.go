@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+func (h Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	readiness := h.service.Health.Readiness()
+
+	statusCode := http.StatusOK
+	if readiness.State != domain.WarmUpReady {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	writeJsonResponse(w, statusCode, readiness)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/gorilla/mux"
+)
+
+func (h Handler) FindOrderHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	uid := mux.Vars(r)["uid"]
+	if !orderUIDPattern.MatchString(uid) {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidOrderUID)
+		return
+	}
+
+	events, err := h.service.Order.History(ctx, uid)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, events)
+}
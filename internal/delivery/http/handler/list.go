@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+const (
+	ordersHtml = "../../web/template/orders.html"
+	dateLayout = "2006-01-02"
+)
+
+type ordersView struct {
+	domain.OrderPage
+	Query   url.Values
+	NextURL string
+}
+
+func (h Handler) FindOrders(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	filter, err := parseOrderFilter(r.URL.Query())
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.service.Order.FindAll(ctx, filter)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, page)
+}
+
+func (h Handler) OrdersPage(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	query := r.URL.Query()
+
+	filter, err := parseOrderFilter(query)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.service.Order.FindAll(ctx, filter)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	view := ordersView{
+		OrderPage: page,
+		Query:     query,
+	}
+
+	if page.NextCursor != "" {
+		next := url.Values{}
+		for key, values := range query {
+			next[key] = values
+		}
+		next.Set("cursor", page.NextCursor)
+		view.NextURL = "/orders?" + next.Encode()
+	}
+
+	tmpl, err := template.ParseFiles(ordersHtml)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	w.Header().Set(contentType, textHtml)
+	w.WriteHeader(http.StatusOK)
+	if err = tmpl.Execute(w, view); err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+}
+
+func parseOrderFilter(query url.Values) (domain.OrderFilter, error) {
+	filter := domain.OrderFilter{
+		CustomerID:      query.Get("customer_id"),
+		TrackNumber:     query.Get("track_number"),
+		DeliveryService: query.Get("delivery_service"),
+		Provider:        query.Get("provider"),
+		Bank:            query.Get("bank"),
+		Currency:        query.Get("currency"),
+		Brand:           query.Get("brand"),
+		Sort:            domain.SortByDateCreated,
+		Desc:            true,
+		Limit:           defaultLimit,
+	}
+
+	var err error
+	if value := query.Get("nm_id"); value != "" {
+		if filter.NmID, err = strconv.Atoi(value); err != nil {
+			return domain.OrderFilter{}, invalidQueryParameter("nm_id")
+		}
+	}
+
+	if value := query.Get("from"); value != "" {
+		if filter.From, err = parseDate(value, false); err != nil {
+			return domain.OrderFilter{}, invalidQueryParameter("from")
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		if filter.To, err = parseDate(value, true); err != nil {
+			return domain.OrderFilter{}, invalidQueryParameter("to")
+		}
+	}
+
+	switch sort := domain.OrderSort(query.Get("sort")); sort {
+	case "":
+	case domain.SortByDateCreated, domain.SortByUID:
+		filter.Sort = sort
+	default:
+		return domain.OrderFilter{}, invalidQueryParameter("sort")
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Desc = false
+	default:
+		return domain.OrderFilter{}, invalidQueryParameter("order")
+	}
+
+	if value := query.Get("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit < 1 || filter.Limit > maxLimit {
+			return domain.OrderFilter{}, ErrInvalidLimit
+		}
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		if filter.After, err = domain.DecodeCursor(value, filter.Sort); err != nil {
+			return domain.OrderFilter{}, err
+		}
+	}
+
+	return filter, nil
+}
+
+// parseDate accepts RFC 3339 timestamps and plain dates. A plain date used as
+// an upper bound includes the whole day.
+func parseDate(value string, upperBound bool) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if upperBound {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return date, nil
+}
+
+func invalidQueryParameter(name string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidQueryParameter, name)
+}
@@ -2,9 +2,13 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"html/template"
 	"net/http"
-	"text/template"
+	"net/url"
+	"regexp"
+	"time"
 
 	"github.com/Be1chenok/levelZero/internal/domain"
 	"github.com/gorilla/mux"
@@ -16,9 +20,55 @@ const (
 	nothingFoundHtml = "../../web/template/nothingFound.html"
 )
 
+const (
+	maxOrderBodyBytes = 1 << 20  // 1 MB
+	maxBatchBodyBytes = 10 << 20 // 10 MB
+	maxBatchSize      = 100
+)
+
+var orderUIDPattern = regexp.MustCompile(`^[a-zA-Z0-9]{1,64}$`)
+
+type orderView struct {
+	domain.Order
+	History []domain.OrderEvent
+}
+
+type batchResult struct {
+	OrderUID   string             `json:"order_uid"`
+	Status     int                `json:"status"`
+	Outcome    domain.Outcome     `json:"outcome,omitempty"`
+	Message    string             `json:"message,omitempty"`
+	Violations []domain.Violation `json:"violations,omitempty"`
+}
+
+// Search redirects to the order with the searched UID, other values are
+// looked up as track numbers. orderUID is the parameter of the old search form.
 func (h Handler) Search(w http.ResponseWriter, r *http.Request) {
-	orderUID := r.URL.Query().Get("orderUID")
-	http.Redirect(w, r, "/order/"+orderUID, http.StatusFound)
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		query = r.URL.Query().Get("orderUID")
+	}
+	if query == "" {
+		http.Redirect(w, r, "/order", http.StatusFound)
+		return
+	}
+
+	if orderUIDPattern.MatchString(query) {
+		_, err := h.service.Order.FindByUID(ctx, query)
+		if err == nil {
+			http.Redirect(w, r, "/order/"+query, http.StatusFound)
+			return
+		}
+		if !errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/tracking/"+url.PathEscape(query), http.StatusFound)
 }
 
 func (h Handler) HomePage(w http.ResponseWriter, r *http.Request) {
@@ -40,12 +90,24 @@ func (h Handler) FindOrderByUID(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
 	defer cancel()
 
+	w.Header().Add(vary, accept)
+
+	mediaType := negotiate(r, textHtml, applicationJson)
+	if mediaType == "" {
+		writeJsonErrorResponse(w, http.StatusNotAcceptable, ErrNotAcceptable)
+		return
+	}
+
 	vars := mux.Vars(r)
 	uid := vars["uid"]
 
 	order, err := h.service.Order.FindByUID(ctx, uid)
 	if err != nil {
 		if errors.Is(err, domain.ErrNothingFound) {
+			if mediaType == applicationJson {
+				writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+				return
+			}
 			h.NothingFound(w, r)
 			return
 		}
@@ -53,6 +115,17 @@ func (h Handler) FindOrderByUID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if mediaType == applicationJson {
+		writeOrder(w, r, formatETag(order.Version), order)
+		return
+	}
+
+	history, err := h.service.Order.History(ctx, uid)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
 	tmpl, err := template.ParseFiles(orderHtml)
 	if err != nil {
 		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
@@ -61,12 +134,284 @@ func (h Handler) FindOrderByUID(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set(contentType, textHtml)
 	w.WriteHeader(http.StatusOK)
-	if err = tmpl.Execute(w, order); err != nil {
+	if err = tmpl.Execute(w, orderView{Order: order, History: history}); err != nil {
 		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
 		return
 	}
 }
 
+func (h Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	if negotiate(r, applicationJson) == "" {
+		writeJsonErrorResponse(w, http.StatusNotAcceptable, ErrNotAcceptable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	uid := vars["uid"]
+	if !orderUIDPattern.MatchString(uid) {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidOrderUID)
+		return
+	}
+
+	base, err := parseBase(r.URL.Query())
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	order, err := h.service.Order.FindByUID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	if base == "" {
+		writeOrder(w, r, formatETag(order.Version), order)
+		return
+	}
+
+	converted, err := h.service.Rate.ConvertOrder(ctx, order, base)
+	if err != nil {
+		statusCode, err := createErrorResponse(err)
+		writeJsonErrorResponse(w, statusCode, err)
+		return
+	}
+
+	writeOrder(w, r, formatConvertedETag(order.Version, converted.Converted), converted)
+}
+
+func (h Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	var order domain.Order
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOrderBodyBytes)).Decode(&order); err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidRequestBody)
+		return
+	}
+
+	createdOrder, outcome, err := h.service.Order.Create(ctx, order)
+	if err != nil {
+		statusCode, err := createErrorResponse(err)
+		writeJsonErrorResponse(w, statusCode, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/orders/"+createdOrder.UID)
+	w.Header().Set(orderOutcome, string(outcome))
+	w.Header().Set(etag, formatETag(createdOrder.Version))
+	writeJsonResponse(w, outcomeStatus(outcome), createdOrder)
+}
+
+// UpdateOrder replaces the order at the version given by If-Match.
+func (h Handler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	uid := mux.Vars(r)["uid"]
+	if !orderUIDPattern.MatchString(uid) {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidOrderUID)
+		return
+	}
+
+	version, ok, err := parseIfMatch(r)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	if !ok {
+		writeJsonErrorResponse(w, http.StatusPreconditionRequired, ErrPreconditionRequired)
+		return
+	}
+
+	var order domain.Order
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOrderBodyBytes)).Decode(&order); err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidRequestBody)
+		return
+	}
+
+	if order.UID == "" {
+		order.UID = uid
+	}
+	if order.UID != uid {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrOrderUIDMismatch)
+		return
+	}
+
+	updatedOrder, outcome, err := h.service.Order.Update(ctx, order, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVersionConflict):
+			writeJsonErrorResponse(w, http.StatusPreconditionFailed, ErrPreconditionFailed)
+		case errors.Is(err, domain.ErrNothingFound):
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+		default:
+			statusCode, err := createErrorResponse(err)
+			writeJsonErrorResponse(w, statusCode, err)
+		}
+		return
+	}
+
+	w.Header().Set(orderOutcome, string(outcome))
+	w.Header().Set(etag, formatETag(updatedOrder.Version))
+	writeJsonResponse(w, http.StatusOK, updatedOrder)
+}
+
+// DeleteOrder deletes the order at the version given by If-Match.
+func (h Handler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	uid := mux.Vars(r)["uid"]
+	if !orderUIDPattern.MatchString(uid) {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidOrderUID)
+		return
+	}
+
+	version, ok, err := parseIfMatch(r)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	if !ok {
+		writeJsonErrorResponse(w, http.StatusPreconditionRequired, ErrPreconditionRequired)
+		return
+	}
+
+	if err := h.service.Order.Delete(ctx, uid, version); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVersionConflict):
+			writeJsonErrorResponse(w, http.StatusPreconditionFailed, ErrPreconditionFailed)
+		case errors.Is(err, domain.ErrNothingFound):
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+		default:
+			statusCode, err := createErrorResponse(err)
+			writeJsonErrorResponse(w, statusCode, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h Handler) CreateOrders(w http.ResponseWriter, r *http.Request) {
+	var orders []domain.Order
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)).Decode(&orders); err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidRequestBody)
+		return
+	}
+
+	if len(orders) == 0 {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrEmptyBatch)
+		return
+	}
+
+	if len(orders) > maxBatchSize {
+		writeJsonErrorResponse(w, http.StatusRequestEntityTooLarge, ErrBatchTooLarge)
+		return
+	}
+
+	// Every order has its own deadline, the write deadline is extended to fit the batch.
+	deadline := time.Now().Add(time.Duration(len(orders)+1) * h.conf.Server.RequestTime)
+	if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	results := make([]batchResult, 0, len(orders))
+	for _, order := range orders {
+		results = append(results, h.createBatchOrder(r.Context(), order))
+	}
+
+	statusCode := results[0].Status
+	for _, result := range results {
+		if result.Status != statusCode {
+			statusCode = http.StatusMultiStatus
+			break
+		}
+	}
+
+	writeJsonResponse(w, statusCode, results)
+}
+
+func (h Handler) createBatchOrder(ctx context.Context, order domain.Order) batchResult {
+	ctx, cancel := context.WithTimeout(ctx, h.conf.Server.RequestTime)
+	defer cancel()
+
+	result := batchResult{
+		OrderUID: order.UID,
+	}
+
+	_, outcome, err := h.service.Order.Create(ctx, order)
+	if err != nil {
+		result.Status, err = createErrorResponse(err)
+		result.Message = err.Error()
+
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			result.Message = domain.ErrInvalidOrder.Error()
+			result.Violations = validationErr.Violations
+		}
+
+		return result
+	}
+
+	result.Status = outcomeStatus(outcome)
+	result.Outcome = outcome
+
+	return result
+}
+
+// writeOrder writes the order with its ETag, or 304 when If-None-Match lists it.
+func writeOrder(w http.ResponseWriter, r *http.Request, tag string, order interface{}) {
+	w.Header().Set(etag, tag)
+
+	if noneMatch(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, order)
+}
+
+func createErrorResponse(err error) (int, error) {
+	var validationErr *domain.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusUnprocessableEntity, validationErr
+	case errors.Is(err, domain.ErrInvalidPayload):
+		return http.StatusUnprocessableEntity, domain.ErrInvalidPayload
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict, domain.ErrConflict
+	case errors.Is(err, domain.ErrVersionConflict):
+		return http.StatusConflict, domain.ErrVersionConflict
+	case errors.Is(err, domain.ErrInvalidRates):
+		return http.StatusUnprocessableEntity, err
+	case errors.Is(err, domain.ErrRateNotFound):
+		return http.StatusUnprocessableEntity, err
+	case errors.Is(err, domain.ErrTemporary):
+		return http.StatusServiceUnavailable, domain.ErrTemporary
+	default:
+		return http.StatusInternalServerError, ErrSomethingWentWrong
+	}
+}
+
+func outcomeStatus(outcome domain.Outcome) int {
+	if outcome == domain.OutcomeCreated {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
 func (h Handler) NothingFound(w http.ResponseWriter, r *http.Request) {
 	tmpl, err := template.ParseFiles(nothingFoundHtml)
 	if err != nil {
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	appService "github.com/Be1chenok/levelZero/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type fakeOrderService struct {
+	appService.Order
+	orders map[string]domain.Order
+}
+
+func (s fakeOrderService) FindByUID(ctx context.Context, orderUID string) (domain.Order, error) {
+	order, ok := s.orders[orderUID]
+	if !ok {
+		return domain.Order{}, domain.ErrNothingFound
+	}
+
+	return order, nil
+}
+
+func newTestHandler(orders ...domain.Order) Handler {
+	service := fakeOrderService{orders: make(map[string]domain.Order)}
+	for _, order := range orders {
+		service.orders[order.UID] = order
+	}
+
+	conf := &config.Config{}
+	conf.Server.RequestTime = time.Second
+
+	return Handler{service: &appService.Service{Order: service}, conf: conf}
+}
+
+func TestFindOrderByUIDVary(t *testing.T) {
+	h := newTestHandler(domain.Order{UID: "b563feb7b2b84b6test", Version: 1})
+
+	tests := []struct {
+		name       string
+		uid        string
+		accept     string
+		wantStatus int
+	}{
+		{name: "json", uid: "b563feb7b2b84b6test", accept: applicationJson, wantStatus: http.StatusOK},
+		{name: "json not found", uid: "missing", accept: applicationJson, wantStatus: http.StatusNotFound},
+		{name: "not acceptable", uid: "b563feb7b2b84b6test", accept: "image/png", wantStatus: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/order/"+tt.uid, nil)
+			r.Header.Set(accept, tt.accept)
+			r = mux.SetURLVars(r, map[string]string{"uid": tt.uid})
+			w := httptest.NewRecorder()
+
+			h.FindOrderByUID(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get(vary); got != accept {
+				t.Errorf("Vary = %q, want %q", got, accept)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+const maxRatesBodyBytes = 10 << 20 // 10 MB
+
+type importResponse struct {
+	Imported int `json:"imported"`
+}
+
+func (h Handler) FindRates(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	query := r.URL.Query()
+	filter := domain.RateFilter{
+		Currency: strings.ToUpper(query.Get("currency")),
+		Base:     strings.ToUpper(query.Get("base")),
+	}
+
+	rates, err := h.service.Rate.FindAll(ctx, filter)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, rates)
+}
+
+// ImportRates stores the rates sent as a JSON array or as a CSV with the
+// header currency,base,rate,effective_at.
+func (h Handler) ImportRates(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get(contentType))
+	if err != nil {
+		mediaType = applicationJson
+	}
+
+	var format string
+	switch mediaType {
+	case applicationJson:
+		format = domain.RatesFormatJSON
+	case textCsv:
+		format = domain.RatesFormatCSV
+	default:
+		writeJsonErrorResponse(w, http.StatusUnsupportedMediaType, ErrUnsupportedMediaType)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRatesBodyBytes))
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidRequestBody)
+		return
+	}
+
+	rates, err := domain.ParseRates(data, format)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	imported, err := h.service.Rate.Import(ctx, rates)
+	if err != nil {
+		statusCode, err := createErrorResponse(err)
+		writeJsonErrorResponse(w, statusCode, err)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, importResponse{Imported: imported})
+}
+
+// Totals sums the payments of all the orders matching the filters of FindOrders
+// in the base currency, the paging parameters are rejected.
+func (h Handler) Totals(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	query := r.URL.Query()
+
+	for _, name := range []string{"cursor", "limit"} {
+		if query.Has(name) {
+			writeJsonErrorResponse(w, http.StatusBadRequest, invalidQueryParameter(name))
+			return
+		}
+	}
+
+	base, err := parseBase(query)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	if base == "" {
+		writeJsonErrorResponse(w, http.StatusBadRequest, invalidQueryParameter("base"))
+		return
+	}
+
+	filter, err := parseOrderFilter(query)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	totals, err := h.service.Rate.Totals(ctx, filter, base)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, totals)
+}
+
+// parseBase returns the currency to convert amounts to, empty when not requested.
+func parseBase(query url.Values) (string, error) {
+	base := strings.ToUpper(query.Get("base"))
+	if base != "" && !domain.IsCurrency(base) {
+		return "", invalidQueryParameter("base")
+	}
+
+	return base, nil
+}
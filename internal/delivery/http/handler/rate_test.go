@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTotalsRejectsPaging(t *testing.T) {
+	h := newTestHandler()
+
+	for _, query := range []string{"base=EUR&limit=10", "base=EUR&cursor=abc"} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/reports/totals?"+query, nil)
+		w := httptest.NewRecorder()
+
+		h.Totals(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Totals(%s) status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
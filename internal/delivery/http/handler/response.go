@@ -2,17 +2,34 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
 )
 
 type errorResponse struct {
-	Message string `json:"message"`
+	Message    string             `json:"message"`
+	Violations []domain.Violation `json:"violations,omitempty"`
+}
+
+func writeJsonResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set(contentType, applicationJson)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
 }
 
 func writeJsonErrorResponse(w http.ResponseWriter, statusCode int, err error) {
 	response := errorResponse{
 		Message: err.Error(),
 	}
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		response.Message = domain.ErrInvalidOrder.Error()
+		response.Violations = validationErr.Violations
+	}
+
 	w.Header().Set(contentType, applicationJson)
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(response)
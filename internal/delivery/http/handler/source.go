@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"regexp"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+const (
+	requestID = "X-Request-ID"
+	actor     = "X-Actor"
+)
+
+// The headers are stored with the order events and shown on the order page,
+// values that do not match are replaced as if the header was missing.
+var (
+	requestIDPattern = regexp.MustCompile(`^[a-zA-Z0-9._:-]{1,128}$`)
+	actorPattern     = regexp.MustCompile(`^[a-zA-Z0-9 ._:@/+=-]{1,128}$`)
+)
+
+// withSource attributes the changes made by the request to the actor named
+// by X-Actor, or to the client address, and to the request ID. The ID is taken
+// from X-Request-ID or generated and is sent back in the same header.
+func (h Handler) withSource(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestID)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestID, id)
+
+		name := r.Header.Get(actor)
+		if !actorPattern.MatchString(name) {
+			name = clientAddress(r)
+		}
+
+		ctx := domain.WithSource(r.Context(), domain.Source{
+			Channel:   domain.ChannelHTTP,
+			Actor:     name,
+			Reference: id,
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
+
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
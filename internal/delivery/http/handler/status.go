@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/gorilla/mux"
+)
+
+const maxTransitionBodyBytes = 1 << 10
+
+type transitionRequest struct {
+	Status domain.OrderStatus `json:"status"`
+}
+
+func (h Handler) ChangeOrderStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	uid := mux.Vars(r)["uid"]
+	if !orderUIDPattern.MatchString(uid) {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidOrderUID)
+		return
+	}
+
+	version, ok, err := parseIfMatch(r)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var request transitionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTransitionBodyBytes)).Decode(&request); err != nil {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidRequestBody)
+		return
+	}
+
+	change, err := h.service.Order.ChangeStatus(ctx, uid, request.Status, version)
+	if err != nil {
+		switch {
+		case ok && errors.Is(err, domain.ErrVersionConflict):
+			writeJsonErrorResponse(w, http.StatusPreconditionFailed, ErrPreconditionFailed)
+		case errors.Is(err, domain.ErrNothingFound):
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+		case errors.Is(err, domain.ErrInvalidStatus), errors.Is(err, domain.ErrIllegalTransition):
+			writeJsonErrorResponse(w, http.StatusUnprocessableEntity, err)
+		default:
+			statusCode, err := createErrorResponse(err)
+			writeJsonErrorResponse(w, statusCode, err)
+		}
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, change)
+}
+
+func (h Handler) FindOrderStatusHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	uid := mux.Vars(r)["uid"]
+	if !orderUIDPattern.MatchString(uid) {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidOrderUID)
+		return
+	}
+
+	history, err := h.service.Order.StatusHistory(ctx, uid)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, history)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/gorilla/mux"
+)
+
+const (
+	trackingHtml         = "../../web/template/tracking.html"
+	maxTrackNumberLength = 64
+)
+
+func (h Handler) Track(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	trackNumber := mux.Vars(r)["trackNumber"]
+	if len(trackNumber) > maxTrackNumberLength {
+		writeJsonErrorResponse(w, http.StatusBadRequest, ErrInvalidTrackNumber)
+		return
+	}
+
+	tracking, err := h.service.Order.Track(ctx, trackNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			writeJsonErrorResponse(w, http.StatusNotFound, domain.ErrNothingFound)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	writeJsonResponse(w, http.StatusOK, tracking)
+}
+
+func (h Handler) TrackingPage(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.conf.Server.RequestTime)
+	defer cancel()
+
+	trackNumber := mux.Vars(r)["trackNumber"]
+	if len(trackNumber) > maxTrackNumberLength {
+		h.NothingFound(w, r)
+		return
+	}
+
+	tracking, err := h.service.Order.Track(ctx, trackNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			h.NothingFound(w, r)
+			return
+		}
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	tmpl, err := template.ParseFiles(trackingHtml)
+	if err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+
+	w.Header().Set(contentType, textHtml)
+	w.WriteHeader(http.StatusOK)
+	if err = tmpl.Execute(w, tracking); err != nil {
+		writeJsonErrorResponse(w, http.StatusInternalServerError, ErrSomethingWentWrong)
+		return
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+type AnalyticsDimension string
+
+const (
+	DimensionSales            AnalyticsDimension = "sales"
+	DimensionBrands           AnalyticsDimension = "brands"
+	DimensionProducts         AnalyticsDimension = "products"
+	DimensionDeliveryServices AnalyticsDimension = "delivery-services"
+	DimensionRegions          AnalyticsDimension = "regions"
+	DimensionCities           AnalyticsDimension = "cities"
+	DimensionProviders        AnalyticsDimension = "providers"
+)
+
+// Bucket is the time span the rows are grouped by, BucketNone covers the whole period.
+type Bucket string
+
+const (
+	BucketNone  Bucket = ""
+	BucketHour  Bucket = "hour"
+	BucketDay   Bucket = "day"
+	BucketWeek  Bucket = "week"
+	BucketMonth Bucket = "month"
+	BucketYear  Bucket = "year"
+)
+
+var dimensions = map[AnalyticsDimension]bool{
+	DimensionSales:            false,
+	DimensionBrands:           true,
+	DimensionProducts:         true,
+	DimensionDeliveryServices: false,
+	DimensionRegions:          false,
+	DimensionCities:           false,
+	DimensionProviders:        false,
+}
+
+type AnalyticsQuery struct {
+	Dimension AnalyticsDimension
+	Bucket    Bucket
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+// AnalyticsRow aggregates the orders of a bucket, a key of the dimension and
+// a currency. Rows of item dimensions count the revenue of the matching items.
+type AnalyticsRow struct {
+	Bucket        *time.Time `json:"bucket,omitempty"`
+	Key           string     `json:"key,omitempty"`
+	Orders        int        `json:"orders"`
+	Items         int        `json:"items"`
+	Revenue       Money      `json:"revenue"`
+	AverageBasket Money      `json:"average_basket"`
+}
+
+func (d AnalyticsDimension) Valid() bool {
+	_, ok := dimensions[d]
+	return ok
+}
+
+// ByItem tells whether the dimension is a field of the items rather than of the order.
+func (d AnalyticsDimension) ByItem() bool {
+	return dimensions[d]
+}
+
+func (b Bucket) Valid() bool {
+	switch b {
+	case BucketNone, BucketHour, BucketDay, BucketWeek, BucketMonth, BucketYear:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type OrderEventType string
+
+const (
+	EventCreated       OrderEventType = "created"
+	EventUpdated       OrderEventType = "updated"
+	EventStatusChanged OrderEventType = "status_changed"
+	EventDeleted       OrderEventType = "deleted"
+)
+
+const (
+	ChannelBroker   = "broker"
+	ChannelHTTP     = "http"
+	ChannelReplay   = "replay"
+	ChannelInternal = "internal"
+)
+
+// Source tells who changed an order and through which channel. Reference is
+// the STAN sequence number, the HTTP request ID or the dead letter ID.
+type Source struct {
+	Channel   string `json:"channel"`
+	Actor     string `json:"actor"`
+	Reference string `json:"reference,omitempty"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type OrderEvent struct {
+	ID       int64          `json:"id"`
+	OrderUID string         `json:"order_uid"`
+	Type     OrderEventType `json:"type"`
+	Source
+	Diff      []FieldChange `json:"diff,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type sourceKey struct{}
+
+func WithSource(ctx context.Context, source Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFromContext returns the source set by WithSource, changes made without
+// one are attributed to the internal channel.
+func SourceFromContext(ctx context.Context) Source {
+	if source, ok := ctx.Value(sourceKey{}).(Source); ok {
+		return source
+	}
+
+	return Source{Channel: ChannelInternal}
+}
+
+// DiffOrders lists the fields that differ between the orders by their JSON
+// paths, such as payment.amount or items[0].price.
+func DiffOrders(before, after Order) []FieldChange {
+	var changes []FieldChange
+	diffValues("", flatten(before), flatten(after), &changes)
+
+	return changes
+}
+
+func flatten(order Order) any {
+	var value any
+
+	data, err := json.Marshal(order)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+
+	return value
+}
+
+func diffValues(path string, before, after any, changes *[]FieldChange) {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if beforeIsMap && afterIsMap {
+		keys := make(map[string]struct{}, len(beforeMap))
+		for key := range beforeMap {
+			keys[key] = struct{}{}
+		}
+		for key := range afterMap {
+			keys[key] = struct{}{}
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			diffValues(field, beforeMap[key], afterMap[key], changes)
+		}
+		return
+	}
+
+	beforeSlice, beforeIsSlice := before.([]any)
+	afterSlice, afterIsSlice := after.([]any)
+	if (beforeIsSlice || before == nil) && (afterIsSlice || after == nil) && (beforeIsSlice || afterIsSlice) {
+		for idx := 0; idx < len(beforeSlice) || idx < len(afterSlice); idx++ {
+			var beforeItem, afterItem any
+			if idx < len(beforeSlice) {
+				beforeItem = beforeSlice[idx]
+			}
+			if idx < len(afterSlice) {
+				afterItem = afterSlice[idx]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, idx), beforeItem, afterItem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, FieldChange{Field: path, From: before, To: after})
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffOrders(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(o *Order)
+		want   []FieldChange
+	}{
+		{
+			name:   "same order",
+			modify: func(o *Order) {},
+		},
+		{
+			name:   "top level field",
+			modify: func(o *Order) { o.Locale = "ru" },
+			want:   []FieldChange{{Field: "locale", From: "en", To: "ru"}},
+		},
+		{
+			name: "nested fields sorted by path",
+			modify: func(o *Order) {
+				o.Payment.Amount = 1917
+				o.Payment.CustomFee = 100
+				o.Delivery.City = "Haifa"
+			},
+			want: []FieldChange{
+				{Field: "delivery.city", From: "Kiryat Mozkin", To: "Haifa"},
+				{Field: "payment.amount", From: float64(1817), To: float64(1917)},
+				{Field: "payment.custom_fee", From: float64(0), To: float64(100)},
+			},
+		},
+		{
+			name:   "item field",
+			modify: func(o *Order) { o.Items[0].Price = 500 },
+			want:   []FieldChange{{Field: "items[0].price", From: float64(453), To: float64(500)}},
+		},
+		{
+			name: "added item",
+			modify: func(o *Order) {
+				o.Items = append(o.Items, Item{ChrtID: 1})
+			},
+			want: []FieldChange{
+				{Field: "items[1]", From: nil, To: flatten(Order{Items: []Item{{ChrtID: 1}}}).(map[string]any)["items"].([]any)[0]},
+			},
+		},
+		{
+			name:   "removed items",
+			modify: func(o *Order) { o.Items = nil },
+			want: []FieldChange{
+				{Field: "items[0]", From: flatten(testOrder()).(map[string]any)["items"].([]any)[0], To: nil},
+			},
+		},
+		{
+			name:   "date created",
+			modify: func(o *Order) { o.DateCreated = o.DateCreated.Add(time.Hour) },
+			want:   []FieldChange{{Field: "date_created", From: "2021-11-26T06:22:19Z", To: "2021-11-26T07:22:19Z"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := testOrder(), testOrder()
+			tt.modify(&after)
+
+			if got := DiffOrders(before, after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffOrders() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type CommandType string
+
+const (
+	CommandCreate CommandType = "create"
+	CommandUpdate CommandType = "update"
+	CommandCancel CommandType = "cancel"
+	CommandDelete CommandType = "delete"
+)
+
+var ErrVersionConflict = errors.New("order has been changed since the given version")
+
+// Command is the envelope of the broker messages. Version is the version of the
+// order the producer has seen, it is required by all commands but create.
+type Command struct {
+	Type     CommandType `json:"type"`
+	Version  int64       `json:"version"`
+	OrderUID string      `json:"order_uid"`
+	Order    *Order      `json:"order"`
+}
+
+// ParseCommand decodes the envelope, a payload without a type is a plain order
+// to create as sent before the envelope was introduced.
+func ParseCommand(data []byte) (Command, error) {
+	var command Command
+	if err := json.Unmarshal(data, &command); err != nil {
+		return Command{}, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	if command.Type == "" {
+		var order Order
+		if err := json.Unmarshal(data, &order); err != nil {
+			return Command{}, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+		}
+		return Command{Type: CommandCreate, OrderUID: order.UID, Order: &order}, nil
+	}
+
+	if command.OrderUID == "" && command.Order != nil {
+		command.OrderUID = command.Order.UID
+	}
+
+	switch command.Type {
+	case CommandCreate, CommandUpdate:
+		if command.Order == nil {
+			return Command{}, fmt.Errorf("%w: %s command without order", ErrInvalidPayload, command.Type)
+		}
+		if command.Order.UID != command.OrderUID {
+			return Command{}, fmt.Errorf("%w: order_uid does not match the order", ErrInvalidPayload)
+		}
+	case CommandCancel, CommandDelete:
+		if command.OrderUID == "" {
+			return Command{}, fmt.Errorf("%w: %s command without order_uid", ErrInvalidPayload, command.Type)
+		}
+	default:
+		return Command{}, fmt.Errorf("%w: unknown command type %s", ErrInvalidPayload, command.Type)
+	}
+
+	if command.Type != CommandCreate && command.Version <= 0 {
+		return Command{}, fmt.Errorf("%w: %s command without version", ErrInvalidPayload, command.Type)
+	}
+
+	return command, nil
+}
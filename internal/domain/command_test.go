@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Command
+		wantErr bool
+	}{
+		{
+			name: "legacy plain order",
+			data: `{"order_uid": "b563feb7b2b84b6test", "track_number": "WBILMTESTTRACK"}`,
+			want: Command{Type: CommandCreate, OrderUID: "b563feb7b2b84b6test"},
+		},
+		{
+			name: "create",
+			data: `{"type": "create", "order": {"order_uid": "b563feb7b2b84b6test"}}`,
+			want: Command{Type: CommandCreate, OrderUID: "b563feb7b2b84b6test"},
+		},
+		{
+			name: "update",
+			data: `{"type": "update", "version": 3, "order": {"order_uid": "b563feb7b2b84b6test"}}`,
+			want: Command{Type: CommandUpdate, Version: 3, OrderUID: "b563feb7b2b84b6test"},
+		},
+		{
+			name: "cancel",
+			data: `{"type": "cancel", "version": 2, "order_uid": "b563feb7b2b84b6test"}`,
+			want: Command{Type: CommandCancel, Version: 2, OrderUID: "b563feb7b2b84b6test"},
+		},
+		{
+			name: "delete",
+			data: `{"type": "delete", "version": 1, "order_uid": "b563feb7b2b84b6test"}`,
+			want: Command{Type: CommandDelete, Version: 1, OrderUID: "b563feb7b2b84b6test"},
+		},
+		{
+			name:    "malformed json",
+			data:    `{"type": "create"`,
+			wantErr: true,
+		},
+		{
+			name:    "legacy order of wrong type",
+			data:    `{"order_uid": 42}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			data:    `{"type": "archive", "version": 1, "order_uid": "b563feb7b2b84b6test"}`,
+			wantErr: true,
+		},
+		{
+			name:    "create without order",
+			data:    `{"type": "create", "order_uid": "b563feb7b2b84b6test"}`,
+			wantErr: true,
+		},
+		{
+			name:    "update without order",
+			data:    `{"type": "update", "version": 1, "order_uid": "b563feb7b2b84b6test"}`,
+			wantErr: true,
+		},
+		{
+			name:    "order_uid does not match the order",
+			data:    `{"type": "update", "version": 1, "order_uid": "other", "order": {"order_uid": "b563feb7b2b84b6test"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "cancel without order_uid",
+			data:    `{"type": "cancel", "version": 1}`,
+			wantErr: true,
+		},
+		{
+			name:    "delete without version",
+			data:    `{"type": "delete", "order_uid": "b563feb7b2b84b6test"}`,
+			wantErr: true,
+		},
+		{
+			name:    "update with negative version",
+			data:    `{"type": "update", "version": -1, "order": {"order_uid": "b563feb7b2b84b6test"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, err := ParseCommand([]byte(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPayload) {
+					t.Fatalf("ParseCommand() error = %v, want %v", err, ErrInvalidPayload)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCommand() error = %v", err)
+			}
+
+			if command.Type != tt.want.Type || command.Version != tt.want.Version || command.OrderUID != tt.want.OrderUID {
+				t.Errorf("ParseCommand() = %+v, want %+v", command, tt.want)
+			}
+			if (command.Type == CommandCreate || command.Type == CommandUpdate) &&
+				(command.Order == nil || command.Order.UID != tt.want.OrderUID) {
+				t.Errorf("ParseCommand() order = %+v, want order %s", command.Order, tt.want.OrderUID)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestIsCurrency(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "USD", want: true},
+		{code: "RUB", want: true},
+		{code: "KWD", want: true},
+		{code: "usd", want: false},
+		{code: "XYZ", want: false},
+		{code: "US", want: false},
+		{code: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := IsCurrency(tt.code); got != tt.want {
+				t.Errorf("IsCurrency(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{code: "USD", want: 2},
+		{code: "EUR", want: 2},
+		{code: "JPY", want: 0},
+		{code: "KRW", want: 0},
+		{code: "KWD", want: 3},
+		{code: "BHD", want: 3},
+		{code: "XYZ", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := MinorUnits(tt.code); got != tt.want {
+				t.Errorf("MinorUnits(%q) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+
+	for code := range minorUnits {
+		if !IsCurrency(code) {
+			t.Errorf("minorUnits lists %s, which is not a currency", code)
+		}
+	}
+}
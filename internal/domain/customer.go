@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// CustomerSummary describes the orders of a customer, LifetimeSpend holds
+// the paid amount in every currency the customer paid in.
+type CustomerSummary struct {
+	CustomerID    string        `json:"customer_id"`
+	Orders        int           `json:"orders"`
+	FirstOrderAt  time.Time     `json:"first_order_at"`
+	LastOrderAt   time.Time     `json:"last_order_at"`
+	LifetimeSpend []Money       `json:"lifetime_spend"`
+	TopBrands     []BrandStat   `json:"top_brands"`
+	Addresses     []AddressStat `json:"addresses"`
+}
+
+type BrandStat struct {
+	Brand string `json:"brand"`
+	Items int    `json:"items"`
+}
+
+type AddressStat struct {
+	Region     string    `json:"region"`
+	City       string    `json:"city"`
+	Address    string    `json:"address"`
+	Zip        string    `json:"zip"`
+	Orders     int       `json:"orders"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type DeadLetter struct {
+	ID            int64      `json:"id"`
+	Subject       string     `json:"subject"`
+	Sequence      uint64     `json:"sequence"`
+	Payload       string     `json:"payload"`
+	Error         string     `json:"error"`
+	Attempts      int        `json:"attempts"`
+	FirstFailedAt time.Time  `json:"first_failed_at"`
+	LastFailedAt  time.Time  `json:"last_failed_at"`
+	ReplayedAt    *time.Time `json:"replayed_at,omitempty"`
+}
+
+type DeadLetterFilter struct {
+	Pending bool
+	Limit   int
+	Offset  int
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalDigits is the number of fractional digits shown for decimals
+// without a finite decimal representation, such as inverted rates.
+const maxDecimalDigits = 20
+
+var ErrInvalidDecimal = errors.New("invalid decimal number")
+
+// Decimal is an exact rational number written in decimal notation, rates are
+// kept as decimals so that conversions are not subject to float rounding.
+type Decimal struct {
+	rat *big.Rat
+}
+
+func NewDecimal(value int64) Decimal {
+	return Decimal{rat: new(big.Rat).SetInt64(value)}
+}
+
+// ParseDecimal reads a number such as 92.5 or 1e-3, fractions like 1/3 are rejected.
+func ParseDecimal(value string) (Decimal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.Contains(value, "/") {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+
+	return Decimal{rat: rat}, nil
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+
+	return d.rat
+}
+
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// Inverse returns 1/d, d must not be zero.
+func (d Decimal) Inverse() Decimal {
+	return Decimal{rat: new(big.Rat).Inv(d.value())}
+}
+
+// Digits returns the number of fractional digits of d, or -1 when d has no finite
+// decimal representation within maxDecimalDigits.
+func (d Decimal) Digits() int {
+	scaled := new(big.Rat).Set(d.value())
+	ten := big.NewRat(10, 1)
+	for digits := 0; digits <= maxDecimalDigits; digits++ {
+		if scaled.IsInt() {
+			return digits
+		}
+		scaled.Mul(scaled, ten)
+	}
+
+	return -1
+}
+
+func (d Decimal) String() string {
+	digits := d.Digits()
+	if digits >= 0 {
+		return d.value().FloatString(digits)
+	}
+
+	return strings.TrimRight(d.value().FloatString(maxDecimalDigits), "0")
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	decimal, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+	*d = decimal
+
+	return nil
+}
+
+// mulRound multiplies the amount by d and 10^exponent and rounds the result
+// half away from zero.
+func (d Decimal) mulRound(amount int64, exponent int) (int64, error) {
+	result := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), d.value())
+
+	power := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil))
+	if exponent >= 0 {
+		result.Mul(result, power)
+	} else {
+		result.Quo(result, power)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(result.Num(), result.Denom(), new(big.Int))
+	if remainder.Mul(remainder.Abs(remainder), big.NewInt(2)).Cmp(result.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(result.Sign())))
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+
+	return quotient.Int64(), nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -3,5 +3,10 @@ package domain
 import "errors"
 
 var (
-	ErrNothingFound = errors.New("nothing found")
+	ErrNothingFound    = errors.New("nothing found")
+	ErrAlreadyExists   = errors.New("already exists")
+	ErrConflict        = errors.New("order with the same uid and different content already exists")
+	ErrAlreadyReplayed = errors.New("already replayed")
+	ErrInvalidPayload  = errors.New("invalid payload")
+	ErrTemporary       = errors.New("temporary failure")
 )
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// OrderChanged notifies the instances that an order was stored by Instance.
+type OrderChanged struct {
+	Instance  string    `json:"instance"`
+	OrderUID  string    `json:"order_uid"`
+	Outcome   Outcome   `json:"outcome"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type OrderSort string
+
+const (
+	SortByDateCreated OrderSort = "date_created"
+	SortByUID         OrderSort = "uid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type OrderFilter struct {
+	CustomerID      string
+	TrackNumber     string
+	DeliveryService string
+	From            time.Time
+	To              time.Time
+	Provider        string
+	Bank            string
+	Currency        string
+	Brand           string
+	NmID            int
+	Sort            OrderSort
+	Desc            bool
+	After           *Cursor
+	Limit           int
+}
+
+type OrderPage struct {
+	Orders     []Order `json:"orders"`
+	Total      int     `json:"total"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// Cursor points to the last order of a page, the next page starts right after it.
+// Value holds the sort column of that order, UID breaks the ties.
+type Cursor struct {
+	Value string `json:"v"`
+	UID   string `json:"u"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes the cursor of a page sorted by sort, the value must be
+// a timestamp when the orders are sorted by date_created.
+func DecodeCursor(value string, sort OrderSort) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.UID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	if sort != SortByUID {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(data string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(data))
+	}
+	dateCursor := Cursor{Value: "2021-11-26T06:22:19.5Z", UID: "b563feb7b2b84b6test"}
+
+	tests := []struct {
+		name    string
+		value   string
+		sort    OrderSort
+		want    Cursor
+		wantErr bool
+	}{
+		{name: "date cursor", value: dateCursor.Encode(), sort: SortByDateCreated, want: dateCursor},
+		{name: "date cursor sorted by uid", value: dateCursor.Encode(), sort: SortByUID, want: dateCursor},
+		{name: "uid cursor", value: encode(`{"v":"","u":"b563feb7b2b84b6test"}`), sort: SortByUID, want: Cursor{UID: "b563feb7b2b84b6test"}},
+		{name: "not base64", value: "!!!", sort: SortByDateCreated, wantErr: true},
+		{name: "not json", value: encode("cursor"), sort: SortByDateCreated, wantErr: true},
+		{name: "missing uid", value: encode(`{"v":"2021-11-26T06:22:19Z"}`), sort: SortByDateCreated, wantErr: true},
+		{name: "value is not a timestamp", value: encode(`{"v":"yesterday","u":"b563feb7b2b84b6test"}`), sort: SortByDateCreated, wantErr: true},
+		{name: "value of wrong type", value: encode(`{"v":42,"u":"b563feb7b2b84b6test"}`), sort: SortByUID, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tt.value, tt.sort)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if *cursor != tt.want {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *cursor, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrAmountOverflow   = errors.New("amount overflows int64")
+)
+
+// Money is an amount in minor units of an ISO 4217 currency, such as cents for USD.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type moneyJSON struct {
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Formatted string `json:"formatted"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	difference := m.Amount - other.Amount
+	if (other.Amount > 0 && difference > m.Amount) || (other.Amount < 0 && difference < m.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return Money{Amount: difference, Currency: m.Currency}, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) String() string {
+	return m.Format("")
+}
+
+// Format renders the amount with the digits of its currency and the separators
+// and symbol placement of the locale, "en" is used for unknown locales.
+func (m Money) Format(locale string) string {
+	format, ok := numberFormats[language(locale)]
+	if !ok {
+		format = numberFormats["en"]
+	}
+
+	// The magnitude is unsigned, -math.MinInt64 does not fit in an int64.
+	amount := uint64(m.Amount)
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := MinorUnits(m.Currency)
+	divisor := uint64(1)
+	for i := 0; i < digits; i++ {
+		divisor *= 10
+	}
+
+	number := group(strconv.FormatUint(amount/divisor, 10), format.group)
+	if digits > 0 {
+		number += format.decimal + fmt.Sprintf("%0*d", digits, amount%divisor)
+	}
+
+	symbol, ok := currencySymbols[m.Currency]
+	if !ok {
+		symbol = m.Currency
+	}
+
+	if format.symbolFirst {
+		if !ok {
+			return sign + symbol + "\u00a0" + number
+		}
+		return sign + symbol + number
+	}
+
+	return sign + number + "\u00a0" + symbol
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount, Currency: m.Currency, Formatted: m.String()})
+}
+
+type numberFormat struct {
+	group       string
+	decimal     string
+	symbolFirst bool
+}
+
+var numberFormats = map[string]numberFormat{
+	"en": {group: ",", decimal: ".", symbolFirst: true},
+	"ru": {group: "\u00a0", decimal: ","},
+	"de": {group: ".", decimal: ","},
+	"fr": {group: "\u202f", decimal: ","},
+	"es": {group: ".", decimal: ","},
+	"it": {group: ".", decimal: ","},
+	"pt": {group: ".", decimal: ","},
+}
+
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"RUB": "₽",
+	"KZT": "₸",
+	"UAH": "₴",
+	"INR": "₹",
+	"KRW": "₩",
+	"TRY": "₺",
+}
+
+func language(locale string) string {
+	locale = strings.ToLower(locale)
+	if idx := strings.IndexAny(locale, "-_"); idx >= 0 {
+		locale = locale[:idx]
+	}
+
+	return locale
+}
+
+func group(number, separator string) string {
+	if len(number) <= 3 {
+		return number
+	}
+
+	var builder strings.Builder
+	head := len(number) % 3
+	if head > 0 {
+		builder.WriteString(number[:head])
+	}
+	for idx := head; idx < len(number); idx += 3 {
+		if builder.Len() > 0 {
+			builder.WriteString(separator)
+		}
+		builder.WriteString(number[idx : idx+3])
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		money  Money
+		locale string
+		want   string
+	}{
+		{name: "en dollars", money: NewMoney(123456, "USD"), locale: "en", want: "$1,234.56"},
+		{name: "en region", money: NewMoney(123456, "USD"), locale: "en_US", want: "$1,234.56"},
+		{name: "unknown locale", money: NewMoney(123456, "USD"), locale: "xx", want: "$1,234.56"},
+		{name: "empty locale", money: NewMoney(5, "USD"), locale: "", want: "$0.05"},
+		{name: "negative", money: NewMoney(-150, "EUR"), locale: "en", want: "-€1.50"},
+		{name: "zero", money: NewMoney(0, "GBP"), locale: "en", want: "£0.00"},
+		{name: "ru rubles", money: NewMoney(123456, "RUB"), locale: "ru", want: "1\u00a0234,56\u00a0₽"},
+		{name: "ru-RU", money: NewMoney(100, "RUB"), locale: "ru-RU", want: "1,00\u00a0₽"},
+		{name: "de euros", money: NewMoney(123456789, "EUR"), locale: "de", want: "1.234.567,89\u00a0€"},
+		{name: "fr euros", money: NewMoney(123456, "EUR"), locale: "fr", want: "1\u202f234,56\u00a0€"},
+		{name: "yen without minor unit", money: NewMoney(1234, "JPY"), locale: "en", want: "¥1,234"},
+		{name: "dinars with three digits", money: NewMoney(1234, "KWD"), locale: "en", want: "KWD\u00a01.234"},
+		{name: "code without symbol", money: NewMoney(5, "KWD"), locale: "de", want: "0,005\u00a0KWD"},
+		{name: "exactly three digits", money: NewMoney(99900, "USD"), locale: "en", want: "$999.00"},
+		{name: "smallest amount", money: NewMoney(math.MinInt64, "USD"), locale: "en", want: "-$92,233,720,368,547,758.08"},
+		{name: "largest amount", money: NewMoney(math.MaxInt64, "JPY"), locale: "en", want: "¥9,223,372,036,854,775,807"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.money.Format(tt.locale); got != tt.want {
+				t.Errorf("Format(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		sum     Money
+		diff    Money
+		sumErr  error
+		diffErr error
+	}{
+		{
+			name: "same currency",
+			a:    NewMoney(150, "USD"),
+			b:    NewMoney(50, "USD"),
+			sum:  NewMoney(200, "USD"),
+			diff: NewMoney(100, "USD"),
+		},
+		{
+			name:    "currency mismatch",
+			a:       NewMoney(150, "USD"),
+			b:       NewMoney(50, "EUR"),
+			sumErr:  ErrCurrencyMismatch,
+			diffErr: ErrCurrencyMismatch,
+		},
+		{
+			name:   "overflow",
+			a:      NewMoney(math.MaxInt64, "USD"),
+			b:      NewMoney(1, "USD"),
+			sumErr: ErrAmountOverflow,
+			diff:   NewMoney(math.MaxInt64-1, "USD"),
+		},
+		{
+			name:    "underflow",
+			a:       NewMoney(math.MinInt64, "USD"),
+			b:       NewMoney(1, "USD"),
+			sum:     NewMoney(math.MinInt64+1, "USD"),
+			diffErr: ErrAmountOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.sumErr) || (tt.sumErr == nil && sum != tt.sum) {
+				t.Errorf("Add() = %v, %v, want %v, %v", sum, err, tt.sum, tt.sumErr)
+			}
+
+			diff, err := tt.a.Sub(tt.b)
+			if !errors.Is(err, tt.diffErr) || (tt.diffErr == nil && diff != tt.diff) {
+				t.Errorf("Sub() = %v, %v, want %v, %v", diff, err, tt.diff, tt.diffErr)
+			}
+		})
+	}
+}
@@ -1,20 +1,40 @@
 package domain
 
+import "time"
+
+type Outcome string
+
+const (
+	OutcomeCreated   Outcome = "created"
+	OutcomeUnchanged Outcome = "unchanged"
+	OutcomeUpdated   Outcome = "updated"
+	OutcomeDeleted   Outcome = "deleted"
+)
+
+type ConflictPolicy string
+
+const (
+	ConflictReject ConflictPolicy = "reject"
+	ConflictUpdate ConflictPolicy = "update"
+)
+
 type Order struct {
-	UID               string   `json:"order_uid"`
-	TrackNumber       string   `json:"track_number"`
-	Entry             string   `json:"entry"`
-	Delivery          Delivery `json:"delivery"`
-	Payment           Payment  `json:"payment"`
-	Items             []Item   `json:"items"`
-	Locale            string   `json:"locale"`
-	InternalSignature string   `json:"internal_signature"`
-	CustomerID        string   `json:"customer_id"`
-	DeliveryService   string   `json:"delivery_service"`
-	ShardKey          string   `json:"shardkey"`
-	SmID              int      `json:"sm_id"`
-	DateCreated       string   `json:"date_created"`
-	OofShard          string   `json:"oof_shard"`
+	UID               string      `json:"order_uid"`
+	TrackNumber       string      `json:"track_number"`
+	Entry             string      `json:"entry"`
+	Delivery          Delivery    `json:"delivery"`
+	Payment           Payment     `json:"payment"`
+	Items             []Item      `json:"items"`
+	Locale            string      `json:"locale"`
+	InternalSignature string      `json:"internal_signature"`
+	CustomerID        string      `json:"customer_id"`
+	DeliveryService   string      `json:"delivery_service"`
+	ShardKey          string      `json:"shardkey"`
+	SmID              int         `json:"sm_id"`
+	DateCreated       time.Time   `json:"date_created"`
+	OofShard          string      `json:"oof_shard"`
+	Status            OrderStatus `json:"status"`
+	Version           int64       `json:"version"`
 }
 
 type Delivery struct {
@@ -32,24 +52,63 @@ type Payment struct {
 	RequestID    string `json:"request_id"`
 	Currency     string `json:"currency"`
 	Provider     string `json:"provider"`
-	Amount       int    `json:"amount"`
-	PaymentDT    int    `json:"payment_dt"`
+	Amount       int64  `json:"amount"`
+	PaymentDT    int64  `json:"payment_dt"`
 	Bank         string `json:"bank"`
-	DeliveryCost int    `json:"delivery_cost"`
-	GoodsTotal   int    `json:"goods_total"`
-	CustomFee    int    `json:"custom_fee"`
+	DeliveryCost int64  `json:"delivery_cost"`
+	GoodsTotal   int64  `json:"goods_total"`
+	CustomFee    int64  `json:"custom_fee"`
 }
 
 type Item struct {
 	ChrtID      int    `json:"chrt_id"`
 	TrackNumber string `json:"track_number"`
-	Price       int    `json:"price"`
+	Price       int64  `json:"price"`
 	RID         string `json:"rid"`
 	Name        string `json:"name"`
 	Sale        int    `json:"sale"`
 	Size        string `json:"size"`
-	TotalPrice  int    `json:"total_price"`
+	TotalPrice  int64  `json:"total_price"`
 	NmID        int    `json:"nm_id"`
 	Brand       string `json:"brand"`
 	Status      int    `json:"status"`
 }
+
+// Size estimates the memory taken by the order in bytes.
+func (o Order) Size() int {
+	size := 256 + len(o.UID) + len(o.TrackNumber) + len(o.Entry) + len(o.Locale) +
+		len(o.InternalSignature) + len(o.CustomerID) + len(o.DeliveryService) +
+		len(o.ShardKey) + len(o.OofShard) + len(o.Status)
+
+	size += len(o.Delivery.Name) + len(o.Delivery.Phone) + len(o.Delivery.Zip) + len(o.Delivery.City) +
+		len(o.Delivery.Address) + len(o.Delivery.Region) + len(o.Delivery.Email)
+
+	size += len(o.Payment.Transaction) + len(o.Payment.RequestID) + len(o.Payment.Currency) +
+		len(o.Payment.Provider) + len(o.Payment.Bank)
+
+	for _, item := range o.Items {
+		size += 128 + len(item.TrackNumber) + len(item.RID) + len(item.Name) + len(item.Size) + len(item.Brand)
+	}
+
+	return size
+}
+
+// NormalizeTime returns t as the database stores it: in UTC with microsecond precision.
+func NormalizeTime(t time.Time) time.Time {
+	return t.UTC().Round(time.Microsecond)
+}
+
+// Money returns the amount, given in minor units, in the payment currency.
+func (p Payment) Money(amount int64) Money {
+	return NewMoney(amount, p.Currency)
+}
+
+// FormatMoney renders the amount in the payment currency for the order locale.
+func (o Order) FormatMoney(amount int64) string {
+	return o.Payment.Money(amount).Format(o.Locale)
+}
+
+// PaidAt returns the payment time, PaymentDT holds it in Unix seconds.
+func (p Payment) PaidAt() time.Time {
+	return time.Unix(p.PaymentDT, 0).UTC()
+}
@@ -0,0 +1,281 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	RatesFormatCSV  = "csv"
+	RatesFormatJSON = "json"
+)
+
+// maxRateDigits is the number of fractional digits of a rate the database keeps.
+const maxRateDigits = 10
+
+var (
+	ErrRateNotFound = errors.New("exchange rate not found")
+	ErrInvalidRates = errors.New("invalid exchange rates")
+)
+
+// ExchangeRate tells that one unit of Currency costs Rate units of Base
+// from EffectiveAt until the next rate of the pair.
+type ExchangeRate struct {
+	Currency    string    `json:"currency"`
+	Base        string    `json:"base"`
+	Rate        Decimal   `json:"rate"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+// exchangeRateJSON is the imported rate, effective_at is parsed like in a CSV.
+type exchangeRateJSON struct {
+	Currency    string  `json:"currency"`
+	Base        string  `json:"base"`
+	Rate        Decimal `json:"rate"`
+	EffectiveAt string  `json:"effective_at"`
+}
+
+type RateFilter struct {
+	Currency string
+	Base     string
+}
+
+// Conversion holds the payment amounts of an order in the base currency.
+type Conversion struct {
+	Base         string    `json:"base"`
+	Rate         Decimal   `json:"rate"`
+	EffectiveAt  time.Time `json:"rate_effective_at"`
+	Amount       Money     `json:"amount"`
+	DeliveryCost Money     `json:"delivery_cost"`
+	GoodsTotal   Money     `json:"goods_total"`
+	CustomFee    Money     `json:"custom_fee"`
+}
+
+type ConvertedOrder struct {
+	Order
+	Converted Conversion `json:"converted"`
+}
+
+// CurrencyTotal sums the payments of the orders paid in one currency at one
+// rate to the base currency, Rate is nil when no rate was effective.
+type CurrencyTotal struct {
+	Currency     string
+	Rate         *ExchangeRate
+	Orders       int
+	Amount       int64
+	DeliveryCost int64
+	GoodsTotal   int64
+	CustomFee    int64
+}
+
+// Totals sums the payments of the orders in the base currency, the amounts
+// of the orders without an effective rate are left out in Unconverted.
+type Totals struct {
+	Base         string  `json:"base"`
+	Orders       int     `json:"orders"`
+	Amount       Money   `json:"amount"`
+	DeliveryCost Money   `json:"delivery_cost"`
+	GoodsTotal   Money   `json:"goods_total"`
+	CustomFee    Money   `json:"custom_fee"`
+	Unconverted  []Money `json:"unconverted,omitempty"`
+}
+
+// Invert returns the rate of Base in Currency.
+func (r ExchangeRate) Invert() ExchangeRate {
+	return ExchangeRate{
+		Currency:    r.Base,
+		Base:        r.Currency,
+		Rate:        r.Rate.Inverse(),
+		EffectiveAt: r.EffectiveAt,
+	}
+}
+
+// Convert converts the money in the rate currency to the rate base, the result
+// is rounded half away from zero to the minor unit of the base.
+func (m Money) Convert(rate ExchangeRate) (Money, error) {
+	if m.Currency != rate.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, rate.Currency)
+	}
+
+	amount, err := rate.Rate.mulRound(m.Amount, MinorUnits(rate.Base)-MinorUnits(rate.Currency))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: rate.Base}, nil
+}
+
+// Convert converts the payment amounts with the rate of the payment currency.
+func (p Payment) Convert(rate ExchangeRate) (Conversion, error) {
+	amounts := []int64{p.Amount, p.DeliveryCost, p.GoodsTotal, p.CustomFee}
+	converted := make([]Money, 0, len(amounts))
+
+	for _, amount := range amounts {
+		money, err := p.Money(amount).Convert(rate)
+		if err != nil {
+			return Conversion{}, err
+		}
+		converted = append(converted, money)
+	}
+
+	return Conversion{
+		Base:         rate.Base,
+		Rate:         rate.Rate,
+		EffectiveAt:  rate.EffectiveAt,
+		Amount:       converted[0],
+		DeliveryCost: converted[1],
+		GoodsTotal:   converted[2],
+		CustomFee:    converted[3],
+	}, nil
+}
+
+func (r ExchangeRate) validate() error {
+	switch {
+	case !IsCurrency(r.Currency):
+		return fmt.Errorf("currency %q is not an ISO 4217 currency code", r.Currency)
+	case !IsCurrency(r.Base):
+		return fmt.Errorf("base %q is not an ISO 4217 currency code", r.Base)
+	case r.Currency == r.Base:
+		return fmt.Errorf("currency and base must differ")
+	case r.Rate.Sign() <= 0:
+		return fmt.Errorf("rate must be a positive number")
+	case r.Rate.Digits() < 0 || r.Rate.Digits() > maxRateDigits:
+		return fmt.Errorf("rate must have at most %d decimal places", maxRateDigits)
+	case r.EffectiveAt.IsZero():
+		return fmt.Errorf("effective_at must not be empty")
+	}
+
+	return nil
+}
+
+// ValidateRates checks the rates before they are stored, the error wraps ErrInvalidRates.
+func ValidateRates(rates []ExchangeRate) error {
+	if len(rates) == 0 {
+		return fmt.Errorf("%w: no rates", ErrInvalidRates)
+	}
+
+	for idx, rate := range rates {
+		if err := rate.validate(); err != nil {
+			return fmt.Errorf("%w: rates[%d]: %v", ErrInvalidRates, idx, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseRates reads a JSON array of rates or a CSV with the header
+// currency,base,rate,effective_at. effective_at is an RFC 3339 timestamp
+// or a plain date.
+func ParseRates(data []byte, format string) ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+
+	switch format {
+	case RatesFormatJSON:
+		var err error
+		if rates, err = parseRatesJSON(data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRates, err)
+		}
+	case RatesFormatCSV:
+		var err error
+		if rates, err = parseRatesCSV(data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRates, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidRates, format)
+	}
+
+	for idx := range rates {
+		rates[idx].Currency = strings.ToUpper(rates[idx].Currency)
+		rates[idx].Base = strings.ToUpper(rates[idx].Base)
+		rates[idx].EffectiveAt = NormalizeTime(rates[idx].EffectiveAt)
+	}
+
+	if err := ValidateRates(rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func parseRatesJSON(data []byte) ([]ExchangeRate, error) {
+	var records []exchangeRateJSON
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+
+	rates := make([]ExchangeRate, 0, len(records))
+	for idx, record := range records {
+		var effectiveAt time.Time
+		if record.EffectiveAt != "" {
+			var err error
+			if effectiveAt, err = parseEffectiveAt(record.EffectiveAt); err != nil {
+				return nil, fmt.Errorf("rates[%d]: invalid effective_at %q", idx, record.EffectiveAt)
+			}
+		}
+
+		rates = append(rates, ExchangeRate{
+			Currency:    record.Currency,
+			Base:        record.Base,
+			Rate:        record.Rate,
+			EffectiveAt: effectiveAt,
+		})
+	}
+
+	return rates, nil
+}
+
+func parseRatesCSV(data []byte) ([]ExchangeRate, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if strings.Join(header, ",") != "currency,base,rate,effective_at" {
+		return nil, fmt.Errorf("header must be currency,base,rate,effective_at")
+	}
+
+	var rates []ExchangeRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rate, err := ParseDecimal(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[2])
+		}
+
+		effectiveAt, err := parseEffectiveAt(record[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid effective_at %q", line, record[3])
+		}
+
+		rates = append(rates, ExchangeRate{
+			Currency:    record[0],
+			Base:        record[1],
+			Rate:        rate,
+			EffectiveAt: effectiveAt,
+		})
+	}
+}
+
+func parseEffectiveAt(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
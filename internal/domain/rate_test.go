@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRates(t *testing.T) {
+	type rate struct {
+		currency, base, rate string
+		effectiveAt          time.Time
+	}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	moment := time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   []rate
+	}{
+		{
+			name:   "csv",
+			format: RatesFormatCSV,
+			data:   "currency,base,rate,effective_at\nUSD,RUB,92.35,2024-01-02\nEUR,USD,1.1,2024-01-02T12:30:00+02:00\n",
+			want: []rate{
+				{currency: "USD", base: "RUB", rate: "92.35", effectiveAt: day},
+				{currency: "EUR", base: "USD", rate: "1.1", effectiveAt: moment},
+			},
+		},
+		{
+			name:   "csv lowercase codes and spaces",
+			format: RatesFormatCSV,
+			data:   "currency,base,rate,effective_at\nusd, rub, 92.3500, 2024-01-02\n",
+			want:   []rate{{currency: "USD", base: "RUB", rate: "92.35", effectiveAt: day}},
+		},
+		{
+			name:   "json",
+			format: RatesFormatJSON,
+			data:   `[{"currency": "USD", "base": "RUB", "rate": 92.35, "effective_at": "2024-01-02T10:30:00Z"}]`,
+			want:   []rate{{currency: "USD", base: "RUB", rate: "92.35", effectiveAt: moment}},
+		},
+		{
+			name:   "json plain date and string rate",
+			format: RatesFormatJSON,
+			data:   `[{"currency": "usd", "base": "rub", "rate": "0.0108283703", "effective_at": "2024-01-02"}]`,
+			want:   []rate{{currency: "USD", base: "RUB", rate: "0.0108283703", effectiveAt: day}},
+		},
+		{
+			name:   "json exponent",
+			format: RatesFormatJSON,
+			data:   `[{"currency": "USD", "base": "RUB", "rate": 9.235e1, "effective_at": "2024-01-02"}]`,
+			want:   []rate{{currency: "USD", base: "RUB", rate: "92.35", effectiveAt: day}},
+		},
+		{name: "unsupported format", format: "xml", data: "<rates/>"},
+		{name: "csv wrong header", format: RatesFormatCSV, data: "code,base,rate,effective_at\nUSD,RUB,92.35,2024-01-02\n"},
+		{name: "csv missing field", format: RatesFormatCSV, data: "currency,base,rate,effective_at\nUSD,RUB,92.35\n"},
+		{name: "csv invalid rate", format: RatesFormatCSV, data: "currency,base,rate,effective_at\nUSD,RUB,abc,2024-01-02\n"},
+		{name: "csv invalid date", format: RatesFormatCSV, data: "currency,base,rate,effective_at\nUSD,RUB,92.35,02.01.2024\n"},
+		{name: "csv no rates", format: RatesFormatCSV, data: "currency,base,rate,effective_at\n"},
+		{name: "json malformed", format: RatesFormatJSON, data: `[{"currency": "USD"`},
+		{name: "json invalid date", format: RatesFormatJSON, data: `[{"currency": "USD", "base": "RUB", "rate": 92.35, "effective_at": "yesterday"}]`},
+		{name: "json fraction rate", format: RatesFormatJSON, data: `[{"currency": "USD", "base": "RUB", "rate": "1/3", "effective_at": "2024-01-02"}]`},
+		{name: "json missing date", format: RatesFormatJSON, data: `[{"currency": "USD", "base": "RUB", "rate": 92.35}]`},
+		{name: "unknown currency", format: RatesFormatJSON, data: `[{"currency": "XYZ", "base": "RUB", "rate": 1, "effective_at": "2024-01-02"}]`},
+		{name: "same currency and base", format: RatesFormatJSON, data: `[{"currency": "RUB", "base": "RUB", "rate": 1, "effective_at": "2024-01-02"}]`},
+		{name: "zero rate", format: RatesFormatJSON, data: `[{"currency": "USD", "base": "RUB", "rate": 0, "effective_at": "2024-01-02"}]`},
+		{name: "negative rate", format: RatesFormatJSON, data: `[{"currency": "USD", "base": "RUB", "rate": -1, "effective_at": "2024-01-02"}]`},
+		{name: "too precise rate", format: RatesFormatJSON, data: `[{"currency": "USD", "base": "RUB", "rate": 0.00000000001, "effective_at": "2024-01-02"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := ParseRates([]byte(tt.data), tt.format)
+			if len(tt.want) == 0 {
+				if !errors.Is(err, ErrInvalidRates) {
+					t.Fatalf("ParseRates() error = %v, want %v", err, ErrInvalidRates)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRates() error = %v", err)
+			}
+
+			if len(rates) != len(tt.want) {
+				t.Fatalf("ParseRates() = %v, want %v", rates, tt.want)
+			}
+			for idx, want := range tt.want {
+				got := rates[idx]
+				if got.Currency != want.currency || got.Base != want.base || got.Rate.String() != want.rate || !got.EffectiveAt.Equal(want.effectiveAt) {
+					t.Errorf("rates[%d] = %v, want %v", idx, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	rate := func(currency, base, value string) ExchangeRate {
+		decimal, err := ParseDecimal(value)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q) error = %v", value, err)
+		}
+		return ExchangeRate{Currency: currency, Base: base, Rate: decimal}
+	}
+
+	tests := []struct {
+		name    string
+		money   Money
+		rate    ExchangeRate
+		want    Money
+		wantErr error
+	}{
+		{name: "dollars to rubles", money: NewMoney(1001, "USD"), rate: rate("USD", "RUB", "92.35"), want: NewMoney(92442, "RUB")},
+		{name: "inverted rate", money: NewMoney(9235, "RUB"), rate: rate("USD", "RUB", "92.35").Invert(), want: NewMoney(100, "USD")},
+		{name: "half rounds away from zero", money: NewMoney(1, "USD"), rate: rate("USD", "EUR", "0.5"), want: NewMoney(1, "EUR")},
+		{name: "negative half", money: NewMoney(-101, "USD"), rate: rate("USD", "EUR", "0.5"), want: NewMoney(-51, "EUR")},
+		{name: "float unsafe product", money: NewMoney(115, "USD"), rate: rate("USD", "EUR", "0.1"), want: NewMoney(12, "EUR")},
+		{name: "to fewer minor units", money: NewMoney(1050, "USD"), rate: rate("USD", "JPY", "150"), want: NewMoney(1575, "JPY")},
+		{name: "to more minor units", money: NewMoney(1, "USD"), rate: rate("USD", "KWD", "0.3075"), want: NewMoney(3, "KWD")},
+		{name: "from no minor units", money: NewMoney(1000, "JPY"), rate: rate("JPY", "USD", "0.0067"), want: NewMoney(670, "USD")},
+		{name: "currency mismatch", money: NewMoney(100, "EUR"), rate: rate("USD", "RUB", "92.35"), wantErr: ErrCurrencyMismatch},
+		{name: "overflow", money: NewMoney(1<<62, "USD"), rate: rate("USD", "RUB", "92.35"), wantErr: ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.money.Convert(tt.rate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != tt.want {
+				t.Errorf("Convert() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
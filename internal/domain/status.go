@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	StatusCreated    OrderStatus = "created"
+	StatusPaid       OrderStatus = "paid"
+	StatusAssembling OrderStatus = "assembling"
+	StatusShipped    OrderStatus = "shipped"
+	StatusDelivered  OrderStatus = "delivered"
+	StatusCancelled  OrderStatus = "cancelled"
+	StatusReturned   OrderStatus = "returned"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid order status")
+	ErrIllegalTransition = errors.New("illegal order status transition")
+)
+
+// transitions lists the statuses an order may move to from each status,
+// cancelled and returned orders are final.
+var transitions = map[OrderStatus][]OrderStatus{
+	StatusCreated:    {StatusPaid, StatusCancelled},
+	StatusPaid:       {StatusAssembling, StatusCancelled},
+	StatusAssembling: {StatusShipped, StatusCancelled},
+	StatusShipped:    {StatusDelivered, StatusReturned},
+	StatusDelivered:  {StatusReturned},
+	StatusCancelled:  {},
+	StatusReturned:   {},
+}
+
+// StatusChange is a transition of an order, From is empty for a new order.
+type StatusChange struct {
+	OrderUID  string      `json:"order_uid"`
+	From      OrderStatus `json:"from,omitempty"`
+	To        OrderStatus `json:"to"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
+
+func (s OrderStatus) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Next lists the statuses the order may move to.
+func (s OrderStatus) Next() []OrderStatus {
+	return transitions[s]
+}
+
+// Transition checks that the order may move from s to next.
+func (s OrderStatus) Transition(next OrderStatus) error {
+	if !next.Valid() {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, next)
+	}
+
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: from %s to %s", ErrIllegalTransition, s, next)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrderStatusTransition(t *testing.T) {
+	tests := []struct {
+		from    OrderStatus
+		to      OrderStatus
+		wantErr error
+	}{
+		{from: StatusCreated, to: StatusPaid},
+		{from: StatusCreated, to: StatusCancelled},
+		{from: StatusCreated, to: StatusShipped, wantErr: ErrIllegalTransition},
+		{from: StatusCreated, to: StatusCreated, wantErr: ErrIllegalTransition},
+		{from: StatusPaid, to: StatusAssembling},
+		{from: StatusPaid, to: StatusCancelled},
+		{from: StatusPaid, to: StatusCreated, wantErr: ErrIllegalTransition},
+		{from: StatusAssembling, to: StatusShipped},
+		{from: StatusAssembling, to: StatusCancelled},
+		{from: StatusAssembling, to: StatusDelivered, wantErr: ErrIllegalTransition},
+		{from: StatusShipped, to: StatusDelivered},
+		{from: StatusShipped, to: StatusReturned},
+		{from: StatusShipped, to: StatusCancelled, wantErr: ErrIllegalTransition},
+		{from: StatusDelivered, to: StatusReturned},
+		{from: StatusDelivered, to: StatusCancelled, wantErr: ErrIllegalTransition},
+		{from: StatusCancelled, to: StatusPaid, wantErr: ErrIllegalTransition},
+		{from: StatusCancelled, to: StatusCancelled, wantErr: ErrIllegalTransition},
+		{from: StatusReturned, to: StatusDelivered, wantErr: ErrIllegalTransition},
+		{from: StatusCreated, to: "lost", wantErr: ErrInvalidStatus},
+		{from: StatusCreated, to: "", wantErr: ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			err := tt.from.Transition(tt.to)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Transition() error = %v", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Transition() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrderStatusNext(t *testing.T) {
+	for status, next := range transitions {
+		for _, to := range next {
+			if !to.Valid() {
+				t.Errorf("%s may move to unknown status %s", status, to)
+			}
+		}
+	}
+}
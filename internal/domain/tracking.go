@@ -0,0 +1,64 @@
+package domain
+
+// Shipment holds the items of an order sent under one track number.
+type Shipment struct {
+	TrackNumber string `json:"track_number"`
+	Items       []Item `json:"items"`
+}
+
+type TrackedOrder struct {
+	OrderUID        string      `json:"order_uid"`
+	TrackNumber     string      `json:"track_number"`
+	Status          OrderStatus `json:"status"`
+	DeliveryService string      `json:"delivery_service"`
+	Shipments       []Shipment  `json:"shipments"`
+}
+
+// Tracking lists the orders with TrackNumber on the order itself or on any of its items.
+type Tracking struct {
+	TrackNumber string         `json:"track_number"`
+	Orders      []TrackedOrder `json:"orders"`
+}
+
+// Shipments groups the items by track number in the order they are listed,
+// items without one are shipped under the order track number.
+func (o Order) Shipments() []Shipment {
+	var shipments []Shipment
+	index := make(map[string]int)
+
+	for _, item := range o.Items {
+		trackNumber := item.TrackNumber
+		if trackNumber == "" {
+			trackNumber = o.TrackNumber
+		}
+
+		idx, ok := index[trackNumber]
+		if !ok {
+			idx = len(shipments)
+			index[trackNumber] = idx
+			shipments = append(shipments, Shipment{TrackNumber: trackNumber})
+		}
+		shipments[idx].Items = append(shipments[idx].Items, item)
+	}
+
+	return shipments
+}
+
+func NewTracking(trackNumber string, orders []Order) Tracking {
+	tracking := Tracking{
+		TrackNumber: trackNumber,
+		Orders:      make([]TrackedOrder, 0, len(orders)),
+	}
+
+	for _, order := range orders {
+		tracking.Orders = append(tracking.Orders, TrackedOrder{
+			OrderUID:        order.UID,
+			TrackNumber:     order.TrackNumber,
+			Status:          order.Status,
+			DeliveryService: order.DeliveryService,
+			Shipments:       order.Shipments(),
+		})
+	}
+
+	return tracking
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderShipments(t *testing.T) {
+	item := func(chrtID int, trackNumber string) Item {
+		return Item{ChrtID: chrtID, TrackNumber: trackNumber}
+	}
+
+	tests := []struct {
+		name  string
+		items []Item
+		want  []Shipment
+	}{
+		{
+			name: "no items",
+		},
+		{
+			name:  "one track number",
+			items: []Item{item(1, "WBILMTESTTRACK"), item(2, "WBILMTESTTRACK")},
+			want: []Shipment{
+				{TrackNumber: "WBILMTESTTRACK", Items: []Item{item(1, "WBILMTESTTRACK"), item(2, "WBILMTESTTRACK")}},
+			},
+		},
+		{
+			name:  "split in listed order",
+			items: []Item{item(1, "B"), item(2, "A"), item(3, "B")},
+			want: []Shipment{
+				{TrackNumber: "B", Items: []Item{item(1, "B"), item(3, "B")}},
+				{TrackNumber: "A", Items: []Item{item(2, "A")}},
+			},
+		},
+		{
+			name:  "item without track number",
+			items: []Item{item(1, ""), item(2, "A"), item(3, "WBILMTESTTRACK")},
+			want: []Shipment{
+				{TrackNumber: "WBILMTESTTRACK", Items: []Item{item(1, ""), item(3, "WBILMTESTTRACK")}},
+				{TrackNumber: "A", Items: []Item{item(2, "A")}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Order{TrackNumber: "WBILMTESTTRACK", Items: tt.items}
+
+			if got := order.Shipments(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Shipments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+const (
+	RuleRequired    = "required"
+	RuleMaxLength   = "max_length"
+	RuleFormat      = "format"
+	RuleMin         = "min"
+	RuleMax         = "max"
+	RuleCurrency    = "currency"
+	RuleConsistency = "consistency"
+)
+
+// totalPriceTolerance allows for the rounding of the sale discount,
+// producers round the discounted price either up or down.
+const totalPriceTolerance = 1
+
+var ErrInvalidOrder = errors.New("invalid order")
+
+var (
+	uidPattern   = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	phonePattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+)
+
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Field+": "+v.Message)
+	}
+
+	return fmt.Sprintf("%v: %s", ErrInvalidOrder, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidOrder
+}
+
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) add(field, rule, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		Field:   field,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) required(field, value string, maxLength int) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, RuleRequired, "must not be empty")
+		return false
+	}
+
+	return v.maxLength(field, value, maxLength)
+}
+
+func (v *validator) maxLength(field, value string, maxLength int) bool {
+	if len(value) > maxLength {
+		v.add(field, RuleMaxLength, "must be at most %d characters long", maxLength)
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) min(field string, value, min int64) {
+	if value < min {
+		v.add(field, RuleMin, "must be at least %d", min)
+	}
+}
+
+func (v *validator) max(field string, value, max int64) {
+	if value > max {
+		v.add(field, RuleMax, "must be at most %d", max)
+	}
+}
+
+// Validate checks the order before it is stored and returns a *ValidationError
+// listing every violated rule. Length limits follow the database schema.
+func (o Order) Validate() error {
+	var v validator
+
+	if v.required("order_uid", o.UID, 64) && !uidPattern.MatchString(o.UID) {
+		v.add("order_uid", RuleFormat, "must contain only latin letters and digits")
+	}
+	v.required("track_number", o.TrackNumber, 64)
+	v.required("entry", o.Entry, 64)
+	v.required("locale", o.Locale, 6)
+	v.maxLength("internal_signature", o.InternalSignature, 64)
+	v.required("customer_id", o.CustomerID, 64)
+	v.required("delivery_service", o.DeliveryService, 64)
+	v.maxLength("shardkey", o.ShardKey, 64)
+	v.min("sm_id", int64(o.SmID), 0)
+	v.maxLength("oof_shard", o.OofShard, 64)
+	if o.DateCreated.IsZero() {
+		v.add("date_created", RuleRequired, "must not be empty")
+	}
+
+	o.Delivery.validate(&v)
+	o.Payment.validate(&v)
+
+	if len(o.Items) == 0 {
+		v.add("items", RuleRequired, "must contain at least one item")
+	}
+
+	goodsTotal := o.Payment.Money(0)
+	var err error
+	for idx, item := range o.Items {
+		item.validate(&v, fmt.Sprintf("items[%d].", idx))
+		if err == nil {
+			goodsTotal, err = goodsTotal.Add(o.Payment.Money(item.TotalPrice))
+		}
+	}
+
+	switch {
+	case err != nil:
+		v.add("payment.goods_total", RuleConsistency, "cannot be compared to the sum of item total prices: %v", err)
+	case len(o.Items) > 0 && o.Payment.GoodsTotal != goodsTotal.Amount:
+		v.add("payment.goods_total", RuleConsistency, "must be equal to the sum of item total prices %s", goodsTotal)
+	}
+
+	o.Payment.validateAmount(&v)
+
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+
+	return nil
+}
+
+func (d Delivery) validate(v *validator) {
+	v.required("delivery.name", d.Name, 64)
+	if v.required("delivery.phone", d.Phone, 16) && !phonePattern.MatchString(d.Phone) {
+		v.add("delivery.phone", RuleFormat, "must be a phone number in international format")
+	}
+	v.required("delivery.zip", d.Zip, 255)
+	v.required("delivery.city", d.City, 255)
+	v.required("delivery.address", d.Address, 255)
+	v.required("delivery.region", d.Region, 255)
+	if v.required("delivery.email", d.Email, 255) {
+		if address, err := mail.ParseAddress(d.Email); err != nil || address.Address != d.Email {
+			v.add("delivery.email", RuleFormat, "must be an email address")
+		}
+	}
+}
+
+func (p Payment) validate(v *validator) {
+	v.required("payment.transaction", p.Transaction, 64)
+	v.maxLength("payment.request_id", p.RequestID, 64)
+	if v.required("payment.currency", p.Currency, 3) && !IsCurrency(p.Currency) {
+		v.add("payment.currency", RuleCurrency, "must be an ISO 4217 currency code")
+	}
+	v.required("payment.provider", p.Provider, 64)
+	v.min("payment.amount", p.Amount, 0)
+	v.min("payment.payment_dt", p.PaymentDT, 0)
+	v.required("payment.bank", p.Bank, 64)
+	v.min("payment.delivery_cost", p.DeliveryCost, 0)
+	v.min("payment.goods_total", p.GoodsTotal, 0)
+	v.min("payment.custom_fee", p.CustomFee, 0)
+}
+
+// validateAmount checks that the amount is the sum of the goods, the delivery and the fee.
+func (p Payment) validateAmount(v *validator) {
+	amount := p.Money(p.GoodsTotal)
+	var err error
+	for _, part := range []int64{p.DeliveryCost, p.CustomFee} {
+		if amount, err = amount.Add(p.Money(part)); err != nil {
+			v.add("payment.amount", RuleConsistency, "cannot be compared to goods_total + delivery_cost + custom_fee: %v", err)
+			return
+		}
+	}
+
+	if p.Amount != amount.Amount {
+		v.add("payment.amount", RuleConsistency, "must be equal to goods_total + delivery_cost + custom_fee %s", amount)
+	}
+}
+
+func (i Item) validate(v *validator, prefix string) {
+	v.min(prefix+"chrt_id", int64(i.ChrtID), 0)
+	v.required(prefix+"track_number", i.TrackNumber, 64)
+	v.min(prefix+"price", i.Price, 0)
+	v.required(prefix+"rid", i.RID, 64)
+	v.required(prefix+"name", i.Name, 64)
+	v.min(prefix+"sale", int64(i.Sale), 0)
+	v.max(prefix+"sale", int64(i.Sale), 100)
+	v.maxLength(prefix+"size", i.Size, 64)
+	v.min(prefix+"total_price", i.TotalPrice, 0)
+	v.min(prefix+"nm_id", int64(i.NmID), 0)
+	v.required(prefix+"brand", i.Brand, 64)
+	v.min(prefix+"status", int64(i.Status), 0)
+
+	// The price is split by hundreds so that the product does not overflow.
+	expected := i.Price/100*int64(100-i.Sale) + i.Price%100*int64(100-i.Sale)/100
+	if diff := i.TotalPrice - expected; diff > totalPriceTolerance || diff < -totalPriceTolerance {
+		v.add(prefix+"total_price", RuleConsistency, "must be equal to the price %d with the sale %d%% applied", i.Price, i.Sale)
+	}
+}
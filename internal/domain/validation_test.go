@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// testOrder returns the sample order of the task, valid as is.
+func testOrder() Order {
+	return Order{
+		UID:         "b563feb7b2b84b6test",
+		TrackNumber: "WBILMTESTTRACK",
+		Entry:       "WBIL",
+		Delivery: Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+		Payment: Payment{
+			Transaction:  "b563feb7b2b84b6test",
+			Currency:     "USD",
+			Provider:     "wbpay",
+			Amount:       1817,
+			PaymentDT:    1637907727,
+			Bank:         "alpha",
+			DeliveryCost: 1500,
+			GoodsTotal:   317,
+			CustomFee:    0,
+		},
+		Items: []Item{{
+			ChrtID:      9934930,
+			TrackNumber: "WBILMTESTTRACK",
+			Price:       453,
+			RID:         "ab4219087a764ae0btest",
+			Name:        "Mascaras",
+			Sale:        30,
+			Size:        "0",
+			TotalPrice:  317,
+			NmID:        2389212,
+			Brand:       "Vivienne Sabo",
+			Status:      202,
+		}},
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		ShardKey:        "9",
+		SmID:            99,
+		DateCreated:     time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		OofShard:        "1",
+	}
+}
+
+func TestOrderValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(o *Order)
+		want   []Violation
+	}{
+		{
+			name:   "valid",
+			modify: func(o *Order) {},
+		},
+		{
+			name:   "empty uid",
+			modify: func(o *Order) { o.UID = " " },
+			want:   []Violation{{Field: "order_uid", Rule: RuleRequired}},
+		},
+		{
+			name:   "uid with symbols",
+			modify: func(o *Order) { o.UID = "b563-feb7" },
+			want:   []Violation{{Field: "order_uid", Rule: RuleFormat}},
+		},
+		{
+			name:   "long locale",
+			modify: func(o *Order) { o.Locale = "en-US-x" },
+			want:   []Violation{{Field: "locale", Rule: RuleMaxLength}},
+		},
+		{
+			name:   "negative sm_id",
+			modify: func(o *Order) { o.SmID = -1 },
+			want:   []Violation{{Field: "sm_id", Rule: RuleMin}},
+		},
+		{
+			name:   "missing date_created",
+			modify: func(o *Order) { o.DateCreated = time.Time{} },
+			want:   []Violation{{Field: "date_created", Rule: RuleRequired}},
+		},
+		{
+			name:   "invalid phone",
+			modify: func(o *Order) { o.Delivery.Phone = "call me" },
+			want:   []Violation{{Field: "delivery.phone", Rule: RuleFormat}},
+		},
+		{
+			name:   "email with display name",
+			modify: func(o *Order) { o.Delivery.Email = "Test <test@gmail.com>" },
+			want:   []Violation{{Field: "delivery.email", Rule: RuleFormat}},
+		},
+		{
+			name:   "unknown currency",
+			modify: func(o *Order) { o.Payment.Currency = "XYZ" },
+			want:   []Violation{{Field: "payment.currency", Rule: RuleCurrency}},
+		},
+		{
+			name:   "lowercase currency",
+			modify: func(o *Order) { o.Payment.Currency = "usd" },
+			want:   []Violation{{Field: "payment.currency", Rule: RuleCurrency}},
+		},
+		{
+			name: "negative delivery cost",
+			modify: func(o *Order) {
+				o.Payment.DeliveryCost = -1
+				o.Payment.Amount = 316
+			},
+			want: []Violation{{Field: "payment.delivery_cost", Rule: RuleMin}},
+		},
+		{
+			name:   "no items",
+			modify: func(o *Order) { o.Items = nil },
+			want:   []Violation{{Field: "items", Rule: RuleRequired}},
+		},
+		{
+			name:   "sale above 100",
+			modify: func(o *Order) { o.Items[0].Sale = 101 },
+			want: []Violation{
+				{Field: "items[0].sale", Rule: RuleMax},
+				{Field: "items[0].total_price", Rule: RuleConsistency},
+			},
+		},
+		{
+			name:   "total price rounded down",
+			modify: func(o *Order) { o.Items[0].TotalPrice, o.Payment.GoodsTotal, o.Payment.Amount = 318, 318, 1818 },
+		},
+		{
+			name: "total price without the sale",
+			modify: func(o *Order) {
+				o.Items[0].TotalPrice, o.Payment.GoodsTotal, o.Payment.Amount = 453, 453, 1953
+			},
+			want: []Violation{{Field: "items[0].total_price", Rule: RuleConsistency}},
+		},
+		{
+			name:   "goods total differs from items",
+			modify: func(o *Order) { o.Payment.GoodsTotal, o.Payment.Amount = 300, 1800 },
+			want:   []Violation{{Field: "payment.goods_total", Rule: RuleConsistency}},
+		},
+		{
+			name:   "amount differs from its parts",
+			modify: func(o *Order) { o.Payment.Amount = 1816 },
+			want:   []Violation{{Field: "payment.amount", Rule: RuleConsistency}},
+		},
+		{
+			name:   "amount without custom fee",
+			modify: func(o *Order) { o.Payment.CustomFee = 100 },
+			want:   []Violation{{Field: "payment.amount", Rule: RuleConsistency}},
+		},
+		{
+			name: "items overflow goods total",
+			modify: func(o *Order) {
+				item := o.Items[0]
+				item.Price, item.Sale, item.TotalPrice = math.MaxInt64, 0, math.MaxInt64
+				o.Items = []Item{item, item}
+			},
+			want: []Violation{
+				{Field: "payment.goods_total", Rule: RuleConsistency},
+			},
+		},
+		{
+			name:   "amount parts overflow",
+			modify: func(o *Order) { o.Payment.CustomFee = math.MaxInt64 },
+			want:   []Violation{{Field: "payment.amount", Rule: RuleConsistency}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := testOrder()
+			tt.modify(&order)
+
+			err := order.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if !errors.Is(err, ErrInvalidOrder) {
+				t.Errorf("Validate() error does not wrap %v", ErrInvalidOrder)
+			}
+
+			if len(validationErr.Violations) != len(tt.want) {
+				t.Fatalf("Validate() violations = %+v, want %+v", validationErr.Violations, tt.want)
+			}
+			for idx, want := range tt.want {
+				got := validationErr.Violations[idx]
+				if got.Field != want.Field || got.Rule != want.Rule {
+					t.Errorf("violation %d = %s %s, want %s %s", idx, got.Field, got.Rule, want.Field, want.Rule)
+				}
+			}
+		})
+	}
+}
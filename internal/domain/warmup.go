@@ -0,0 +1,29 @@
+package domain
+
+type WarmUpState string
+
+const (
+	WarmUpWarming WarmUpState = "warming"
+	WarmUpReady   WarmUpState = "ready"
+	WarmUpFailed  WarmUpState = "failed"
+)
+
+// WarmUpFailurePolicy decides what happens when loading the cache fails.
+type WarmUpFailurePolicy string
+
+const (
+	// WarmUpFailureServe serves with a partially loaded cache.
+	WarmUpFailureServe WarmUpFailurePolicy = "serve"
+	// WarmUpFailureRetry keeps retrying the load while the service is warming.
+	WarmUpFailureRetry WarmUpFailurePolicy = "retry"
+	// WarmUpFailureExit stops the service.
+	WarmUpFailureExit WarmUpFailurePolicy = "exit"
+)
+
+type WarmUp struct {
+	State    WarmUpState `json:"state"`
+	Loaded   int         `json:"loaded"`
+	Total    int         `json:"total"`
+	Progress float64     `json:"progress"`
+	Error    string      `json:"error,omitempty"`
+}
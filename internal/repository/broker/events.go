@@ -0,0 +1,45 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/nats-io/nats.go"
+)
+
+type Events interface {
+	PublishOrderChanged(event domain.OrderChanged) error
+}
+
+// events publishes on core NATS, the notifications are not persisted
+// and are received only by the instances connected at the moment.
+type events struct {
+	nc      *nats.Conn
+	subject string
+}
+
+func NewEvents(conf *config.Config, nc *nats.Conn) Events {
+	return &events{
+		nc:      nc,
+		subject: conf.Stan.EventsSubject,
+	}
+}
+
+func (e events) PublishOrderChanged(event domain.OrderChanged) error {
+	if e.subject == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := e.nc.Publish(e.subject, data); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	return nil
+}
@@ -1,78 +1,227 @@
 package cache
 
 import (
-	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/Be1chenok/levelZero/internal/repository/postgres"
-	appLogger "github.com/Be1chenok/levelZero/logger"
-	"go.uber.org/zap"
+	"github.com/Be1chenok/levelZero/internal/config"
 )
 
-var ErrAlreadyExists = errors.New("already exixts")
+const defaultEntrySize = 1024
 
-type Cache interface {
-	LoadToCache(ctx context.Context) error
-	Get(key string) (interface{}, bool)
-	Set(key string, value interface{}) error
+var ErrTooLarge = errors.New("value is larger than the cache capacity")
+
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V) error
+	// SetIfAbsent keeps the cached value of the key and reports whether value was set.
+	SetIfAbsent(key K, value V) (bool, error)
+	// Delete removes the key and reports whether it was cached.
+	Delete(key K) (bool, error)
+	// DeletePrefix removes the keys starting with prefix and returns their number.
+	DeletePrefix(prefix string) (int, error)
+	Flush() error
+	Stats() Stats
+}
+
+// Ranger is implemented by caches that can iterate over their entries.
+type Ranger[K comparable, V any] interface {
+	Range(fn func(key K, value V) bool)
+}
+
+// SizeFunc estimates the memory taken by a value in bytes.
+type SizeFunc[V any] func(value V) int64
+
+type Stats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+	Entries     int    `json:"entries"`
+	Bytes       int64  `json:"bytes"`
 }
 
-type cache struct {
-	logger        appLogger.Logger
-	postgresOrder postgres.Order
-	mutex         sync.RWMutex
-	data          map[string]interface{}
+type cache[K comparable, V any] struct {
+	conf   config.CacheConfig
+	sizeOf SizeFunc[V]
+	mutex  sync.Mutex
+	data   map[K]*entry[K, V]
+	policy policy[K, V]
+	bytes  int64
+	stats  Stats
 }
 
-func New(postgresOrder postgres.Order, logger appLogger.Logger) Cache {
-	return &cache{
-		data:          make(map[string]interface{}),
-		postgresOrder: postgresOrder,
-		logger:        logger.With(zap.String("component", "cache")),
+func New[K comparable, V any](conf config.CacheConfig, sizeOf SizeFunc[V]) (Cache[K, V], error) {
+	policy, err := newPolicy[K, V](conf.Policy)
+	if err != nil {
+		return nil, err
 	}
+
+	if sizeOf == nil {
+		sizeOf = func(V) int64 { return defaultEntrySize }
+	}
+
+	return &cache[K, V]{
+		conf:   conf,
+		sizeOf: sizeOf,
+		data:   make(map[K]*entry[K, V]),
+		policy: policy,
+	}, nil
 }
 
-func (c *cache) Get(key string) (interface{}, bool) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	value, ok := c.data[key]
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	var zero V
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.data[key]
 	if !ok {
-		return nil, false
+		c.stats.Misses++
+		return zero, false
+	}
+
+	if e.expired(time.Now()) {
+		c.remove(e)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return zero, false
 	}
 
-	return value, true
+	c.policy.touch(e)
+	c.stats.Hits++
+
+	return e.value, true
+}
+
+func (c *cache[K, V]) Set(key K, value V) error {
+	_, err := c.set(key, value, false)
+	return err
+}
+
+func (c *cache[K, V]) SetIfAbsent(key K, value V) (bool, error) {
+	return c.set(key, value, true)
 }
 
-func (c *cache) Set(key string, value interface{}) error {
-	c.mutex.RLock()
-	if _, ok := c.data[key]; ok == true {
-		return ErrAlreadyExists
+func (c *cache[K, V]) set(key K, value V, ifAbsent bool) (bool, error) {
+	size := c.sizeOf(value)
+	if c.conf.MaxBytes > 0 && size > c.conf.MaxBytes {
+		return false, ErrTooLarge
 	}
-	c.mutex.RUnlock()
 
 	c.mutex.Lock()
-	c.data[key] = value
-	c.mutex.Unlock()
+	defer c.mutex.Unlock()
 
-	return nil
+	if e, ok := c.data[key]; ok {
+		if ifAbsent && !e.expired(time.Now()) {
+			return false, nil
+		}
+		c.remove(e)
+	}
+
+	e := &entry[K, V]{
+		key:   key,
+		value: value,
+		size:  size,
+	}
+	if c.conf.TTL > 0 {
+		e.expiresAt = time.Now().Add(c.conf.TTL)
+	}
+
+	for c.exceeds(1, size) {
+		victim := c.policy.victim()
+		if victim == nil {
+			break
+		}
+		c.remove(victim)
+		c.stats.Evictions++
+	}
+
+	c.data[key] = e
+	c.policy.add(e)
+	c.bytes += size
+
+	return true, nil
 }
 
-func (c *cache) LoadToCache(ctx context.Context) error {
-	orders, err := c.postgresOrder.FindAllOrders(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to find all orders: %w", err)
+func (c *cache[K, V]) Delete(key K) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.data[key]
+	if !ok {
+		return false, nil
 	}
+	c.remove(e)
+
+	return true, nil
+}
 
-	c.logger.Info("loading cache")
+func (c *cache[K, V]) DeletePrefix(prefix string) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-	for _, order := range orders {
-		if err := c.Set(order.UID, order); err != nil {
-			return fmt.Errorf("filed to set data: %w", err)
+	deleted := 0
+	for key, e := range c.data {
+		if strings.HasPrefix(fmt.Sprint(key), prefix) {
+			c.remove(e)
+			deleted++
 		}
 	}
-	c.logger.Infof("loaded to cache %v orders", len(orders))
+
+	return deleted, nil
+}
+
+func (c *cache[K, V]) Flush() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, e := range c.data {
+		c.remove(e)
+	}
 
 	return nil
 }
+
+func (c *cache[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.data)
+	stats.Bytes = c.bytes
+
+	return stats
+}
+
+// Range calls fn for every entry that has not expired until fn returns false.
+// The cache is locked while fn runs.
+func (c *cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for key, e := range c.data {
+		if e.expired(now) {
+			continue
+		}
+		if !fn(key, e.value) {
+			return
+		}
+	}
+}
+
+// exceeds reports whether adding entries of size bytes would go over the capacity.
+func (c *cache[K, V]) exceeds(entries int, size int64) bool {
+	return (c.conf.MaxEntries > 0 && len(c.data)+entries > c.conf.MaxEntries) ||
+		(c.conf.MaxBytes > 0 && c.bytes+size > c.conf.MaxBytes)
+}
+
+func (c *cache[K, V]) remove(e *entry[K, V]) {
+	c.policy.remove(e)
+	delete(c.data, e.key)
+	c.bytes -= e.size
+}
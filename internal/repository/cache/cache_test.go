@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+)
+
+func TestCacheEviction(t *testing.T) {
+	type op struct {
+		set string
+		get string
+	}
+
+	tests := []struct {
+		name      string
+		conf      config.CacheConfig
+		ops       []op
+		want      []string
+		evictions uint64
+	}{
+		{
+			name: "lru evicts the least recently used",
+			conf: config.CacheConfig{Policy: PolicyLRU, MaxEntries: 2},
+			ops:  []op{{set: "a"}, {set: "b"}, {get: "a"}, {set: "c"}},
+			want: []string{"a", "c"}, evictions: 1,
+		},
+		{
+			name: "lru is the default policy",
+			conf: config.CacheConfig{MaxEntries: 2},
+			ops:  []op{{set: "a"}, {set: "b"}, {get: "a"}, {set: "c"}},
+			want: []string{"a", "c"}, evictions: 1,
+		},
+		{
+			name: "lru counts recent use, not frequency",
+			conf: config.CacheConfig{Policy: PolicyLRU, MaxEntries: 2},
+			ops:  []op{{set: "a"}, {set: "b"}, {get: "a"}, {get: "a"}, {get: "b"}, {set: "c"}},
+			want: []string{"b", "c"}, evictions: 1,
+		},
+		{
+			name: "lfu evicts the least frequently used",
+			conf: config.CacheConfig{Policy: PolicyLFU, MaxEntries: 2},
+			ops:  []op{{set: "a"}, {set: "b"}, {get: "a"}, {get: "a"}, {get: "b"}, {set: "c"}},
+			want: []string{"a", "c"}, evictions: 1,
+		},
+		{
+			name: "lfu breaks ties by recent use",
+			conf: config.CacheConfig{Policy: PolicyLFU, MaxEntries: 2},
+			ops:  []op{{set: "a"}, {set: "b"}, {get: "a"}, {get: "b"}, {set: "c"}},
+			want: []string{"b", "c"}, evictions: 1,
+		},
+		{
+			name: "lfu evicts new entries first",
+			conf: config.CacheConfig{Policy: PolicyLFU, MaxEntries: 2},
+			ops:  []op{{set: "a"}, {get: "a"}, {set: "b"}, {set: "c"}, {set: "d"}},
+			want: []string{"a", "d"}, evictions: 2,
+		},
+		{
+			name: "max bytes",
+			conf: config.CacheConfig{Policy: PolicyLRU, MaxBytes: 2 * defaultEntrySize},
+			ops:  []op{{set: "a"}, {set: "b"}, {set: "c"}},
+			want: []string{"b", "c"}, evictions: 1,
+		},
+		{
+			name: "replacing a key does not evict",
+			conf: config.CacheConfig{Policy: PolicyLFU, MaxEntries: 2},
+			ops:  []op{{set: "a"}, {set: "b"}, {set: "a"}},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "unbounded",
+			conf: config.CacheConfig{Policy: PolicyLFU},
+			ops:  []op{{set: "a"}, {set: "b"}, {set: "c"}},
+			want: []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New[string, string](tt.conf, nil)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			for _, op := range tt.ops {
+				if op.set != "" {
+					if err := c.Set(op.set, op.set); err != nil {
+						t.Fatalf("Set(%s) error = %v", op.set, err)
+					}
+				}
+				if op.get != "" {
+					c.Get(op.get)
+				}
+			}
+
+			var keys []string
+			c.(Ranger[string, string]).Range(func(key, _ string) bool {
+				keys = append(keys, key)
+				return true
+			})
+			sort.Strings(keys)
+
+			if len(keys) != len(tt.want) {
+				t.Fatalf("cached keys = %v, want %v", keys, tt.want)
+			}
+			for idx := range keys {
+				if keys[idx] != tt.want[idx] {
+					t.Fatalf("cached keys = %v, want %v", keys, tt.want)
+				}
+			}
+
+			if evictions := c.Stats().Evictions; evictions != tt.evictions {
+				t.Errorf("Stats().Evictions = %d, want %d", evictions, tt.evictions)
+			}
+		})
+	}
+}
+
+func TestCacheUnknownPolicy(t *testing.T) {
+	if _, err := New[string, string](config.CacheConfig{Policy: "fifo"}, nil); err == nil {
+		t.Error("New() with an unknown policy succeeded")
+	}
+}
+
+func TestCacheTooLarge(t *testing.T) {
+	c, err := New[string, string](config.CacheConfig{MaxBytes: 10}, func(value string) int64 { return int64(len(value)) })
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := c.Set("a", "too large value"); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Set() error = %v, want %v", err, ErrTooLarge)
+	}
+	if err := c.Set("b", "fits"); err != nil {
+		t.Errorf("Set() error = %v", err)
+	}
+	if bytes := c.Stats().Bytes; bytes != 4 {
+		t.Errorf("Stats().Bytes = %d, want 4", bytes)
+	}
+}
+
+func TestCacheExpiration(t *testing.T) {
+	c, err := New[string, string](config.CacheConfig{TTL: time.Millisecond}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := c.Set("a", "a"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get() of an expired entry = ok")
+	}
+
+	stats := c.Stats()
+	if stats.Expirations != 1 || stats.Misses != 1 || stats.Entries != 0 {
+		t.Errorf("Stats() = %+v, want 1 expiration, 1 miss and no entries", stats)
+	}
+
+	set, err := c.SetIfAbsent("a", "b")
+	if err != nil || !set {
+		t.Errorf("SetIfAbsent() = %v, %v, want true, nil", set, err)
+	}
+}
+
+func TestCacheSetIfAbsent(t *testing.T) {
+	c, err := New[string, string](config.CacheConfig{}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		value   string
+		wantSet bool
+		want    string
+	}{
+		{value: "newer", wantSet: true, want: "newer"},
+		{value: "older", wantSet: false, want: "newer"},
+	}
+
+	for _, tt := range tests {
+		set, err := c.SetIfAbsent("a", tt.value)
+		if err != nil {
+			t.Fatalf("SetIfAbsent() error = %v", err)
+		}
+		if set != tt.wantSet {
+			t.Errorf("SetIfAbsent(%s) = %v, want %v", tt.value, set, tt.wantSet)
+		}
+		if got, _ := c.Get("a"); got != tt.want {
+			t.Errorf("Get() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+	appLogger "github.com/Be1chenok/levelZero/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendTiered = "tiered"
+
+	defaultLoadBatchSize = 1000
+)
+
+var errFull = errors.New("cache is full")
+
+type Order interface {
+	Cache[string, domain.Order]
+	LoadToCache(ctx context.Context) error
+	WarmUp(ctx context.Context) error
+	WarmUpStatus() domain.WarmUp
+	Invalidate(orderUID string) error
+	Snapshot() error
+	RunSnapshots(ctx context.Context)
+}
+
+type order struct {
+	Cache[string, domain.Order]
+	local         Cache[string, domain.Order]
+	shared        Cache[string, domain.Order]
+	conf          *config.Config
+	logger        appLogger.Logger
+	postgresOrder postgres.Order
+	mutex         sync.RWMutex
+	warmUp        domain.WarmUp
+
+	// The keys deleted while the cache is loading, so that the orders read from
+	// the database before their deletion are not cached again.
+	loadMutex       sync.Mutex
+	loading         bool
+	dropped         map[string]struct{}
+	droppedPrefixes []string
+}
+
+// NewOrder creates the order cache with the backend selected by CACHE_BACKEND,
+// the Redis client is required by the redis and tiered backends only.
+func NewOrder(conf *config.Config, postgresOrder postgres.Order, client *redis.Client, logger appLogger.Logger) (Order, error) {
+	logger = logger.With(zap.String("component", "cache"))
+
+	o := &order{
+		conf:          conf,
+		postgresOrder: postgresOrder,
+		logger:        logger,
+		warmUp:        domain.WarmUp{State: domain.WarmUpWarming},
+	}
+
+	if conf.Cache.Backend == BackendRedis || conf.Cache.Backend == BackendTiered {
+		if client == nil {
+			return nil, fmt.Errorf("redis client is required by %s backend", conf.Cache.Backend)
+		}
+		o.shared = NewRedis[string, domain.Order](client, logger, conf.Redis.KeyPrefix, conf.Cache.TTL, conf.Redis.Timeout)
+	}
+
+	switch conf.Cache.Backend {
+	case "", BackendMemory:
+		local, err := New[string, domain.Order](conf.Cache, orderSize)
+		if err != nil {
+			return nil, err
+		}
+		o.local = local
+		o.Cache = local
+	case BackendRedis:
+		o.Cache = o.shared
+	case BackendTiered:
+		local, err := New[string, domain.Order](conf.Cache, orderSize)
+		if err != nil {
+			return nil, err
+		}
+		o.local = local
+		o.Cache = NewTiered(local, o.shared, logger)
+	default:
+		return nil, fmt.Errorf("unknown cache backend: %s", conf.Cache.Backend)
+	}
+
+	return o, nil
+}
+
+// LoadToCache fills the cache from the snapshot and Postgres. The orders are
+// set only if absent, the ones cached meanwhile are newer than the loaded ones,
+// and the ones deleted or invalidated meanwhile are skipped.
+func (o *order) LoadToCache(ctx context.Context) error {
+	o.beginLoad()
+	defer o.endLoad()
+
+	if o.shared != nil && o.shared.Stats().Entries > 0 {
+		o.logger.Info("shared cache is already loaded")
+		o.setProgress(0, 0)
+		return nil
+	}
+
+	batchSize := o.conf.Cache.LoadBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultLoadBatchSize
+	}
+
+	since, loaded, err := o.loadSnapshot(ctx, batchSize)
+	if err != nil {
+		if !errors.Is(err, errFull) {
+			return err
+		}
+		o.logger.Infof("cache is full, stopped loading")
+		return nil
+	}
+
+	count, err := o.postgresOrder.CountOrders(ctx, since)
+	if err != nil {
+		return fmt.Errorf("failed to count orders: %w", err)
+	}
+	total := loaded + count
+	o.setProgress(loaded, total)
+
+	o.logger.Infof("loading cache: %v orders", count)
+
+	if err := o.postgresOrder.LoadOrders(ctx, since, batchSize, func(orders []domain.Order) error {
+		for _, order := range orders {
+			if o.full() {
+				return errFull
+			}
+			if err := o.setLoaded(order); err != nil {
+				return err
+			}
+			loaded++
+		}
+
+		o.setProgress(loaded, total)
+		o.logger.Infof("loaded to cache %v/%v orders (%.1f%%)", loaded, total, percent(loaded, total))
+
+		return nil
+	}); err != nil {
+		if !errors.Is(err, errFull) {
+			return fmt.Errorf("failed to load orders: %w", err)
+		}
+		o.logger.Infof("cache is full, stopped loading")
+	}
+
+	o.logger.Infof("loaded to cache %v orders", loaded)
+
+	return nil
+}
+
+// loadSnapshot fills the cache from the snapshot file and returns the creation
+// time of its newest order, orders created since then are loaded from Postgres.
+// The snapshotted orders are checked against the stored versions in batches,
+// deleted and changed orders are dropped, the changed ones are loaded on first read.
+// A missing or corrupted snapshot is not an error, the cache is loaded in full.
+func (o *order) loadSnapshot(ctx context.Context, batchSize int) (time.Time, int, error) {
+	if o.conf.Cache.SnapshotPath == "" {
+		return time.Time{}, 0, nil
+	}
+
+	orders, since, err := readSnapshot(o.conf.Cache.SnapshotPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			o.logger.Warnf("failed to read snapshot, loading cache from database: %v", err)
+		}
+		return time.Time{}, 0, nil
+	}
+
+	loaded := 0
+	for start := 0; start < len(orders); start += batchSize {
+		batch := orders[start:min(start+batchSize, len(orders))]
+
+		uids := make([]string, 0, len(batch))
+		for _, order := range batch {
+			uids = append(uids, order.UID)
+		}
+
+		versions, err := o.postgresOrder.FindOrderVersions(ctx, uids)
+		if err != nil {
+			return time.Time{}, 0, fmt.Errorf("failed to find order versions: %w", err)
+		}
+
+		for _, order := range batch {
+			if version, ok := versions[order.UID]; !ok || version != order.Version {
+				continue
+			}
+			if o.full() {
+				return time.Time{}, 0, errFull
+			}
+			if err := o.setLoaded(order); err != nil {
+				return time.Time{}, 0, err
+			}
+			loaded++
+		}
+	}
+
+	o.logger.Infof("loaded to cache %v of %v orders from snapshot, newest created at %v", loaded, len(orders), since)
+
+	return since, loaded, nil
+}
+
+// WarmUp loads the cache and tracks its progress. A failed load is handled
+// according to CACHE_WARMUP_FAILURE, the error is returned by the exit policy only.
+func (o *order) WarmUp(ctx context.Context) error {
+	for {
+		err := o.LoadToCache(ctx)
+		if err == nil {
+			o.setState(domain.WarmUpReady, nil)
+			o.logger.Info("cache is ready")
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		switch domain.WarmUpFailurePolicy(o.conf.Cache.WarmUpFailure) {
+		case domain.WarmUpFailureRetry:
+			o.setState(domain.WarmUpWarming, err)
+			o.logger.Errorf("failed to load cache, retrying in %v: %v", o.conf.Cache.WarmUpBackoff, err)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(o.conf.Cache.WarmUpBackoff):
+			}
+		case domain.WarmUpFailureExit:
+			o.setState(domain.WarmUpFailed, err)
+			return err
+		default:
+			o.setState(domain.WarmUpReady, err)
+			o.logger.Errorf("failed to load cache, serving partially loaded cache: %v", err)
+			return nil
+		}
+	}
+}
+
+func (o *order) WarmUpStatus() domain.WarmUp {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return o.warmUp
+}
+
+func (o *order) setProgress(loaded, total int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.warmUp.Loaded = loaded
+	o.warmUp.Total = total
+	o.warmUp.Progress = percent(loaded, total)
+}
+
+func (o *order) setState(state domain.WarmUpState, err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.warmUp.State = state
+	o.warmUp.Error = ""
+	if err != nil {
+		o.warmUp.Error = err.Error()
+	}
+}
+
+// Snapshot writes the cached orders to the snapshot file. Caches that cannot
+// iterate over their entries, like Redis, and caches that are still warming
+// are not snapshotted.
+func (o *order) Snapshot() error {
+	if o.conf.Cache.SnapshotPath == "" || o.WarmUpStatus().State != domain.WarmUpReady {
+		return nil
+	}
+
+	ranger, ok := o.Cache.(Ranger[string, domain.Order])
+	if !ok {
+		return nil
+	}
+
+	var orders []domain.Order
+	ranger.Range(func(_ string, order domain.Order) bool {
+		orders = append(orders, order)
+		return true
+	})
+
+	since, err := writeSnapshot(o.conf.Cache.SnapshotPath, orders)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot cache: %w", err)
+	}
+
+	o.logger.Infof("snapshotted %v orders, newest created at %v", len(orders), since)
+
+	return nil
+}
+
+// RunSnapshots snapshots the cache every CACHE_SNAPSHOT_INTERVAL and once more
+// when ctx is done.
+func (o *order) RunSnapshots(ctx context.Context) {
+	if o.conf.Cache.SnapshotPath == "" {
+		return
+	}
+
+	var tick <-chan time.Time
+	if o.conf.Cache.SnapshotInterval > 0 {
+		ticker := time.NewTicker(o.conf.Cache.SnapshotInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			if err := o.Snapshot(); err != nil {
+				o.logger.Error(err)
+			}
+		case <-ctx.Done():
+			if err := o.Snapshot(); err != nil {
+				o.logger.Error(err)
+			}
+			return
+		}
+	}
+}
+
+func (o *order) beginLoad() {
+	o.loadMutex.Lock()
+	defer o.loadMutex.Unlock()
+
+	o.loading = true
+	o.dropped = make(map[string]struct{})
+	o.droppedPrefixes = nil
+}
+
+func (o *order) endLoad() {
+	o.loadMutex.Lock()
+	defer o.loadMutex.Unlock()
+
+	o.loading = false
+	o.dropped = nil
+	o.droppedPrefixes = nil
+}
+
+// drop remembers the deleted keys while the cache is loading, all of them
+// when prefix is empty.
+func (o *order) drop(key string, prefix bool) {
+	o.loadMutex.Lock()
+	defer o.loadMutex.Unlock()
+
+	if !o.loading {
+		return
+	}
+
+	if prefix {
+		o.droppedPrefixes = append(o.droppedPrefixes, key)
+		return
+	}
+	o.dropped[key] = struct{}{}
+}
+
+// setLoaded caches the loaded order unless it was deleted since the load started.
+// The check and the set are done under loadMutex, a concurrent deletion is either
+// seen by the check or deletes the order after it is set.
+func (o *order) setLoaded(order domain.Order) error {
+	o.loadMutex.Lock()
+	defer o.loadMutex.Unlock()
+
+	if _, ok := o.dropped[order.UID]; ok {
+		return nil
+	}
+	for _, prefix := range o.droppedPrefixes {
+		if strings.HasPrefix(order.UID, prefix) {
+			return nil
+		}
+	}
+
+	if _, err := o.SetIfAbsent(order.UID, order); err != nil {
+		return fmt.Errorf("filed to set data: %w", err)
+	}
+
+	return nil
+}
+
+func (o *order) Delete(key string) (bool, error) {
+	o.drop(key, false)
+
+	return o.Cache.Delete(key)
+}
+
+func (o *order) DeletePrefix(prefix string) (int, error) {
+	o.drop(prefix, true)
+
+	return o.Cache.DeletePrefix(prefix)
+}
+
+func (o *order) Flush() error {
+	o.drop("", true)
+
+	return o.Cache.Flush()
+}
+
+// Invalidate removes the order from the in-memory cache of this instance,
+// the shared cache is kept up to date by the instance that changed the order.
+func (o *order) Invalidate(orderUID string) error {
+	if o.local == nil {
+		return nil
+	}
+	o.drop(orderUID, false)
+
+	if _, err := o.local.Delete(orderUID); err != nil {
+		return fmt.Errorf("failed to delete order: %w", err)
+	}
+
+	return nil
+}
+
+// full reports whether the in-memory cache reached its capacity, the shared
+// cache is bounded by the Redis eviction settings instead.
+func (o *order) full() bool {
+	if o.shared != nil {
+		return false
+	}
+
+	stats := o.Stats()
+
+	return (o.conf.Cache.MaxEntries > 0 && stats.Entries >= o.conf.Cache.MaxEntries) ||
+		(o.conf.Cache.MaxBytes > 0 && stats.Bytes >= o.conf.Cache.MaxBytes)
+}
+
+func orderSize(order domain.Order) int64 {
+	return int64(order.Size())
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return float64(part) * 100 / float64(total)
+}
@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+	"go.uber.org/zap"
+)
+
+// fakePostgresOrder serves the stored orders to the cache loader.
+type fakePostgresOrder struct {
+	postgres.Order
+	orders []domain.Order
+	// beforeLoad runs between reading a batch and passing it to the cache.
+	beforeLoad func()
+}
+
+func (p *fakePostgresOrder) CountOrders(ctx context.Context, since time.Time) (int, error) {
+	count := 0
+	for _, order := range p.orders {
+		if !order.DateCreated.Before(since) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (p *fakePostgresOrder) LoadOrders(ctx context.Context, since time.Time, batchSize int, load func(orders []domain.Order) error) error {
+	var orders []domain.Order
+	for _, order := range p.orders {
+		if !order.DateCreated.Before(since) {
+			orders = append(orders, order)
+		}
+	}
+
+	if p.beforeLoad != nil {
+		p.beforeLoad()
+	}
+
+	return load(orders)
+}
+
+func (p *fakePostgresOrder) FindOrderVersions(ctx context.Context, orderUIDs []string) (map[string]int64, error) {
+	versions := make(map[string]int64)
+	for _, order := range p.orders {
+		for _, orderUID := range orderUIDs {
+			if order.UID == orderUID {
+				versions[order.UID] = order.Version
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+func newTestOrderCache(t *testing.T, conf config.CacheConfig, postgresOrder postgres.Order) Order {
+	t.Helper()
+
+	o, err := NewOrder(&config.Config{Cache: conf}, postgresOrder, nil, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("NewOrder() error = %v", err)
+	}
+
+	return o
+}
+
+func TestLoadToCacheValidatesSnapshot(t *testing.T) {
+	snapshotted := testOrders()
+	deleted := domain.Order{UID: "d563feb7b2b84b6test", DateCreated: snapshotted[1].DateCreated.Add(time.Minute), Version: 1}
+	snapshotted = append(snapshotted, deleted)
+
+	unchanged, changed := snapshotted[0], snapshotted[1]
+	changed.Version++
+	created := domain.Order{UID: "e563feb7b2b84b6test", DateCreated: changed.DateCreated.Add(time.Hour), Version: 1}
+
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	if _, err := writeSnapshot(path, snapshotted); err != nil {
+		t.Fatalf("writeSnapshot() error = %v", err)
+	}
+
+	postgresOrder := &fakePostgresOrder{orders: []domain.Order{unchanged, changed, created}}
+	o := newTestOrderCache(t, config.CacheConfig{SnapshotPath: path, LoadBatchSize: 2}, postgresOrder)
+
+	if err := o.LoadToCache(context.Background()); err != nil {
+		t.Fatalf("LoadToCache() error = %v", err)
+	}
+
+	tests := []struct {
+		uid         string
+		wantVersion int64
+		wantCached  bool
+	}{
+		{uid: unchanged.UID, wantVersion: unchanged.Version, wantCached: true},
+		{uid: changed.UID},
+		{uid: deleted.UID},
+		{uid: created.UID, wantVersion: created.Version, wantCached: true},
+	}
+
+	for _, tt := range tests {
+		got, ok := o.Get(tt.uid)
+		if ok != tt.wantCached {
+			t.Errorf("Get(%s) ok = %v, want %v", tt.uid, ok, tt.wantCached)
+			continue
+		}
+		if ok && got.Version != tt.wantVersion {
+			t.Errorf("Get(%s) version = %d, want %d", tt.uid, got.Version, tt.wantVersion)
+		}
+	}
+}
+
+func TestLoadToCacheSkipsDroppedOrders(t *testing.T) {
+	orders := testOrders()
+
+	tests := []struct {
+		name       string
+		drop       func(o Order) error
+		wantCached []bool
+	}{
+		{name: "nothing dropped", drop: func(o Order) error { return nil }, wantCached: []bool{true, true}},
+		{
+			name:       "deleted",
+			drop:       func(o Order) error { _, err := o.Delete(orders[0].UID); return err },
+			wantCached: []bool{false, true},
+		},
+		{
+			name:       "invalidated",
+			drop:       func(o Order) error { return o.Invalidate(orders[1].UID) },
+			wantCached: []bool{true, false},
+		},
+		{
+			name:       "deleted by prefix",
+			drop:       func(o Order) error { _, err := o.DeletePrefix("b563"); return err },
+			wantCached: []bool{false, true},
+		},
+		{
+			name:       "flushed",
+			drop:       func(o Order) error { return o.Flush() },
+			wantCached: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgresOrder := &fakePostgresOrder{orders: orders}
+			o := newTestOrderCache(t, config.CacheConfig{}, postgresOrder)
+			postgresOrder.beforeLoad = func() {
+				if err := tt.drop(o); err != nil {
+					t.Errorf("drop error = %v", err)
+				}
+			}
+
+			if err := o.LoadToCache(context.Background()); err != nil {
+				t.Fatalf("LoadToCache() error = %v", err)
+			}
+
+			for idx, order := range orders {
+				if _, ok := o.Get(order.UID); ok != tt.wantCached[idx] {
+					t.Errorf("Get(%s) ok = %v, want %v", order.UID, ok, tt.wantCached[idx])
+				}
+			}
+
+			postgresOrder.beforeLoad = nil
+			if err := o.LoadToCache(context.Background()); err != nil {
+				t.Fatalf("LoadToCache() error = %v", err)
+			}
+			for _, order := range orders {
+				if _, ok := o.Get(order.UID); !ok {
+					t.Errorf("Get(%s) after the next load = false, want true", order.UID)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadToCacheConcurrentDelete(t *testing.T) {
+	var orders []domain.Order
+	for idx := 0; idx < 100; idx++ {
+		orders = append(orders, domain.Order{UID: fmt.Sprintf("order%03d", idx), Version: 1})
+	}
+
+	postgresOrder := &fakePostgresOrder{orders: orders}
+	o := newTestOrderCache(t, config.CacheConfig{}, postgresOrder)
+
+	started := make(chan struct{})
+	postgresOrder.beforeLoad = func() { close(started) }
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := o.LoadToCache(context.Background()); err != nil {
+			t.Errorf("LoadToCache() error = %v", err)
+		}
+	}()
+
+	<-started
+	for idx := 0; idx < len(orders); idx += 2 {
+		wg.Add(1)
+		go func(orderUID string) {
+			defer wg.Done()
+			if _, err := o.Delete(orderUID); err != nil {
+				t.Errorf("Delete() error = %v", err)
+			}
+		}(orders[idx].UID)
+	}
+	wg.Wait()
+
+	for idx, order := range orders {
+		if _, ok := o.Get(order.UID); ok != (idx%2 == 1) {
+			t.Errorf("Get(%s) ok = %v, want %v", order.UID, ok, idx%2 == 1)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"time"
+)
+
+const (
+	PolicyLRU = "lru"
+	PolicyLFU = "lfu"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	size      int64
+	expiresAt time.Time
+	frequency int
+	element   *list.Element
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// policy decides which entry is evicted when the cache is full.
+type policy[K comparable, V any] interface {
+	add(e *entry[K, V])
+	touch(e *entry[K, V])
+	remove(e *entry[K, V])
+	victim() *entry[K, V]
+}
+
+func newPolicy[K comparable, V any](name string) (policy[K, V], error) {
+	switch name {
+	case "", PolicyLRU:
+		return &lru[K, V]{entries: list.New()}, nil
+	case PolicyLFU:
+		return &lfu[K, V]{frequencies: make(map[int]*list.List)}, nil
+	default:
+		return nil, fmt.Errorf("unknown eviction policy: %s", name)
+	}
+}
+
+// lru keeps entries from the most to the least recently used.
+type lru[K comparable, V any] struct {
+	entries *list.List
+}
+
+func (p *lru[K, V]) add(e *entry[K, V]) {
+	e.element = p.entries.PushFront(e)
+}
+
+func (p *lru[K, V]) touch(e *entry[K, V]) {
+	p.entries.MoveToFront(e.element)
+}
+
+func (p *lru[K, V]) remove(e *entry[K, V]) {
+	p.entries.Remove(e.element)
+}
+
+func (p *lru[K, V]) victim() *entry[K, V] {
+	back := p.entries.Back()
+	if back == nil {
+		return nil
+	}
+
+	return back.Value.(*entry[K, V])
+}
+
+// lfu groups entries by access frequency, the least recently used entry
+// of the lowest frequency is evicted first.
+type lfu[K comparable, V any] struct {
+	frequencies  map[int]*list.List
+	minFrequency int
+}
+
+func (p *lfu[K, V]) add(e *entry[K, V]) {
+	e.frequency = 1
+	p.push(e)
+	p.minFrequency = 1
+}
+
+func (p *lfu[K, V]) touch(e *entry[K, V]) {
+	p.remove(e)
+	e.frequency++
+	p.push(e)
+}
+
+func (p *lfu[K, V]) remove(e *entry[K, V]) {
+	entries := p.frequencies[e.frequency]
+	entries.Remove(e.element)
+
+	if entries.Len() == 0 {
+		delete(p.frequencies, e.frequency)
+		if p.minFrequency == e.frequency {
+			p.minFrequency = p.lowestFrequency()
+		}
+	}
+}
+
+func (p *lfu[K, V]) victim() *entry[K, V] {
+	entries, ok := p.frequencies[p.minFrequency]
+	if !ok {
+		return nil
+	}
+
+	return entries.Back().Value.(*entry[K, V])
+}
+
+func (p *lfu[K, V]) push(e *entry[K, V]) {
+	entries, ok := p.frequencies[e.frequency]
+	if !ok {
+		entries = list.New()
+		p.frequencies[e.frequency] = entries
+	}
+
+	e.element = entries.PushFront(e)
+	if e.frequency < p.minFrequency || len(p.frequencies) == 1 {
+		p.minFrequency = e.frequency
+	}
+}
+
+func (p *lfu[K, V]) lowestFrequency() int {
+	lowest := 0
+	for frequency := range p.frequencies {
+		if lowest == 0 || frequency < lowest {
+			lowest = frequency
+		}
+	}
+
+	return lowest
+}
@@ -0,0 +1,258 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	appLogger "github.com/Be1chenok/levelZero/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	scanCount = 1000
+
+	// countTTL is how long the number of keys is reused, counting scans the keyspace.
+	countTTL = 10 * time.Second
+)
+
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// redisCache keeps values as JSON in a Redis database shared by all instances,
+// under keys starting with the prefix.
+type redisCache[K comparable, V any] struct {
+	client  *redis.Client
+	logger  appLogger.Logger
+	prefix  string
+	ttl     time.Duration
+	timeout time.Duration
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+
+	countMutex sync.Mutex
+	entries    int
+	countedAt  time.Time
+}
+
+func NewRedis[K comparable, V any](client *redis.Client, logger appLogger.Logger, prefix string, ttl, timeout time.Duration) Cache[K, V] {
+	return &redisCache[K, V]{
+		client:  client,
+		logger:  logger,
+		prefix:  prefix,
+		ttl:     ttl,
+		timeout: timeout,
+	}
+}
+
+func (c *redisCache[K, V]) Get(key K) (V, bool) {
+	var value V
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.logger.Errorf("failed to get %v from redis: %v", key, err)
+		}
+		c.misses.Add(1)
+		return value, false
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		c.logger.Errorf("failed to unmarshal %v from redis: %v", key, err)
+		c.misses.Add(1)
+		return value, false
+	}
+
+	c.hits.Add(1)
+
+	return value, true
+}
+
+func (c *redisCache[K, V]) Set(key K, value V) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if err := c.client.Set(ctx, c.key(key), data, c.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set value in redis: %w", err)
+	}
+
+	return nil
+}
+
+func (c *redisCache[K, V]) SetIfAbsent(key K, value V) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	set, err := c.client.SetNX(ctx, c.key(key), data, c.ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to set value in redis: %w", err)
+	}
+
+	return set, nil
+}
+
+func (c *redisCache[K, V]) Delete(key K) (bool, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	deleted, err := c.client.Del(ctx, c.key(key)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete value from redis: %w", err)
+	}
+
+	return deleted > 0, nil
+}
+
+// DeletePrefix scans the keys starting with the cache prefix followed by prefix
+// and deletes them batch by batch.
+func (c *redisCache[K, V]) DeletePrefix(prefix string) (int, error) {
+	defer c.resetCount()
+
+	pattern := redisGlobEscaper.Replace(c.prefix+prefix) + "*"
+
+	deleted := 0
+	var cursor uint64
+	for {
+		count, next, err := c.deleteBatch(cursor, pattern)
+		deleted += count
+		if err != nil {
+			return deleted, err
+		}
+
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
+
+func (c *redisCache[K, V]) deleteBatch(cursor uint64, pattern string) (int, uint64, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	keys, next, err := c.client.Scan(ctx, cursor, pattern, scanCount).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to scan keys in redis: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return 0, next, nil
+	}
+
+	deleted, err := c.client.Del(ctx, keys...).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete values from redis: %w", err)
+	}
+
+	return int(deleted), next, nil
+}
+
+// Flush deletes the keys of the cache, other keys of the database are kept.
+func (c *redisCache[K, V]) Flush() error {
+	_, err := c.DeletePrefix("")
+	return err
+}
+
+// Stats reports the number of keys counted at most countTTL ago, it is counted
+// again after keys are deleted by prefix.
+func (c *redisCache[K, V]) Stats() Stats {
+	stats := Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+
+	entries, err := c.cachedCount()
+	if err != nil {
+		c.logger.Errorf("failed to count keys in redis: %v", err)
+		return stats
+	}
+	stats.Entries = entries
+
+	return stats
+}
+
+func (c *redisCache[K, V]) cachedCount() (int, error) {
+	c.countMutex.Lock()
+	defer c.countMutex.Unlock()
+
+	if !c.countedAt.IsZero() && time.Since(c.countedAt) < countTTL {
+		return c.entries, nil
+	}
+
+	entries, err := c.count()
+	if err != nil {
+		return 0, err
+	}
+	c.entries, c.countedAt = entries, time.Now()
+
+	return entries, nil
+}
+
+func (c *redisCache[K, V]) resetCount() {
+	c.countMutex.Lock()
+	defer c.countMutex.Unlock()
+
+	c.countedAt = time.Time{}
+}
+
+// count scans the keys starting with the cache prefix batch by batch,
+// other keys of the database are not counted.
+func (c *redisCache[K, V]) count() (int, error) {
+	pattern := redisGlobEscaper.Replace(c.prefix) + "*"
+
+	count := 0
+	var cursor uint64
+	for {
+		keys, next, err := c.scanBatch(cursor, pattern)
+		if err != nil {
+			return count, err
+		}
+		count += len(keys)
+
+		if next == 0 {
+			return count, nil
+		}
+		cursor = next
+	}
+}
+
+func (c *redisCache[K, V]) scanBatch(cursor uint64, pattern string) ([]string, uint64, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	keys, next, err := c.client.Scan(ctx, cursor, pattern, scanCount).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan keys in redis: %w", err)
+	}
+
+	return keys, next, nil
+}
+
+func (c *redisCache[K, V]) key(key K) string {
+	return c.prefix + fmt.Sprint(key)
+}
+
+func (c *redisCache[K, V]) context() (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), c.timeout)
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type testValue struct {
+	Name    string `json:"name"`
+	Version int64  `json:"version"`
+}
+
+func newTestRedis(t *testing.T, prefix string) (*miniredis.Miniredis, Cache[string, testValue]) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return server, NewRedis[string, testValue](client, zap.NewNop().Sugar(), prefix, 0, time.Second)
+}
+
+func TestRedisGetSet(t *testing.T) {
+	server, cache := newTestRedis(t, "order:")
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Get() of a missing key = ok")
+	}
+
+	want := testValue{Name: "a", Version: 1}
+	if err := cache.Set("a", want); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !server.Exists("order:a") {
+		t.Error("Set() did not store the prefixed key")
+	}
+
+	got, ok := cache.Get("a")
+	if !ok || got != want {
+		t.Errorf("Get() = %+v, %v, want %+v, true", got, ok, want)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Stats() hits = %d, misses = %d, want 1, 1", stats.Hits, stats.Misses)
+	}
+}
+
+func TestRedisSetIfAbsent(t *testing.T) {
+	_, cache := newTestRedis(t, "order:")
+
+	tests := []struct {
+		name    string
+		value   testValue
+		wantSet bool
+		want    testValue
+	}{
+		{name: "absent", value: testValue{Name: "a", Version: 2}, wantSet: true, want: testValue{Name: "a", Version: 2}},
+		{name: "present", value: testValue{Name: "a", Version: 1}, wantSet: false, want: testValue{Name: "a", Version: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := cache.SetIfAbsent("a", tt.value)
+			if err != nil {
+				t.Fatalf("SetIfAbsent() error = %v", err)
+			}
+			if set != tt.wantSet {
+				t.Errorf("SetIfAbsent() = %v, want %v", set, tt.wantSet)
+			}
+
+			if got, _ := cache.Get("a"); got != tt.want {
+				t.Errorf("Get() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisKeysOutsidePrefix(t *testing.T) {
+	server, cache := newTestRedis(t, "order:")
+
+	server.Set("session:1", "x")
+	server.Set("order-archive", "x")
+	server.Set("order:*", "x")
+
+	for _, key := range []string{"a", "ab", "b"} {
+		if err := cache.Set(key, testValue{Name: key}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	if entries := cache.Stats().Entries; entries != 4 {
+		t.Errorf("Stats().Entries = %d, want 4", entries)
+	}
+
+	deleted, err := cache.DeletePrefix("a")
+	if err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeletePrefix() = %d, want 2", deleted)
+	}
+
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if entries := cache.Stats().Entries; entries != 0 {
+		t.Errorf("Stats().Entries after Flush() = %d, want 0", entries)
+	}
+
+	for _, key := range []string{"session:1", "order-archive"} {
+		if !server.Exists(key) {
+			t.Errorf("Flush() deleted %s outside the prefix", key)
+		}
+	}
+}
+
+func TestRedisDelete(t *testing.T) {
+	_, cache := newTestRedis(t, "order:")
+
+	if err := cache.Set("a", testValue{Name: "a"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	for _, want := range []bool{true, false} {
+		deleted, err := cache.Delete("a")
+		if err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if deleted != want {
+			t.Errorf("Delete() = %v, want %v", deleted, want)
+		}
+	}
+}
+
+func TestRedisTTL(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	cache := NewRedis[string, testValue](client, zap.NewNop().Sugar(), "order:", time.Minute, time.Second)
+	if err := cache.Set("a", testValue{Name: "a"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	server.FastForward(2 * time.Minute)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Get() of an expired key = ok")
+	}
+}
+
+func TestRedisStatsCountsKeysOnce(t *testing.T) {
+	server, cache := newTestRedis(t, "order:")
+
+	if err := cache.Set("a", testValue{Name: "a"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if entries := cache.Stats().Entries; entries != 1 {
+		t.Fatalf("Stats().Entries = %d, want 1", entries)
+	}
+
+	server.Set("order:b", "x")
+	if entries := cache.Stats().Entries; entries != 1 {
+		t.Errorf("Stats().Entries = %d, want the counted 1", entries)
+	}
+
+	if _, err := cache.DeletePrefix("a"); err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	if entries := cache.Stats().Entries; entries != 1 {
+		t.Errorf("Stats().Entries after DeletePrefix() = %d, want 1", entries)
+	}
+}
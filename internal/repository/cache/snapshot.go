@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+const snapshotVersion = 5
+
+var ErrCorruptedSnapshot = errors.New("snapshot is corrupted")
+
+// snapshot is stored as gob, Orders holds the gzipped gob of the cached orders.
+type snapshot struct {
+	Version    int
+	CreatedAt  time.Time
+	MaxCreated time.Time
+	Count      int
+	Checksum   [sha256.Size]byte
+	Orders     []byte
+}
+
+func writeSnapshot(path string, orders []domain.Order) (time.Time, error) {
+	var maxCreated time.Time
+	for _, order := range orders {
+		if createdAt := order.DateCreated; createdAt.After(maxCreated) {
+			maxCreated = createdAt
+		}
+	}
+
+	var payload bytes.Buffer
+	zw := gzip.NewWriter(&payload)
+	if err := gob.NewEncoder(zw).Encode(orders); err != nil {
+		return time.Time{}, fmt.Errorf("failed to encode orders: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return time.Time{}, fmt.Errorf("failed to compress orders: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := gob.NewEncoder(file).Encode(snapshot{
+		Version:    snapshotVersion,
+		CreatedAt:  time.Now().UTC(),
+		MaxCreated: maxCreated,
+		Count:      len(orders),
+		Checksum:   sha256.Sum256(payload.Bytes()),
+		Orders:     payload.Bytes(),
+	}); err != nil {
+		file.Close()
+		return time.Time{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return time.Time{}, fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return time.Time{}, fmt.Errorf("failed to replace snapshot file: %w", err)
+	}
+
+	return maxCreated, nil
+}
+
+// readSnapshot returns the orders of the snapshot and the creation time
+// of the newest of them.
+func readSnapshot(path string) ([]domain.Order, time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	var s snapshot
+	if err := gob.NewDecoder(file).Decode(&s); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %w", ErrCorruptedSnapshot, err)
+	}
+
+	if s.Version != snapshotVersion {
+		return nil, time.Time{}, fmt.Errorf("%w: unsupported version %d", ErrCorruptedSnapshot, s.Version)
+	}
+
+	if sha256.Sum256(s.Orders) != s.Checksum {
+		return nil, time.Time{}, fmt.Errorf("%w: checksum mismatch", ErrCorruptedSnapshot)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(s.Orders))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %w", ErrCorruptedSnapshot, err)
+	}
+
+	var orders []domain.Order
+	if err := gob.NewDecoder(zr).Decode(&orders); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %w", ErrCorruptedSnapshot, err)
+	}
+
+	if len(orders) != s.Count {
+		return nil, time.Time{}, fmt.Errorf("%w: expected %d orders, got %d", ErrCorruptedSnapshot, s.Count, len(orders))
+	}
+
+	return orders, s.MaxCreated, nil
+}
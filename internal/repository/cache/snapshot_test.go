@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+func testOrders() []domain.Order {
+	return []domain.Order{
+		{
+			UID:         "b563feb7b2b84b6test",
+			TrackNumber: "WBILMTESTTRACK",
+			Payment:     domain.Payment{Currency: "USD", Amount: 1817},
+			Items:       []domain.Item{{ChrtID: 9934930, Price: 453, TotalPrice: 317}},
+			DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+			Status:      domain.StatusCreated,
+			Version:     1,
+		},
+		{
+			UID:         "c563feb7b2b84b6test",
+			TrackNumber: "WBILMTESTTRACK2",
+			DateCreated: time.Date(2021, 11, 27, 6, 22, 19, 0, time.UTC),
+			Status:      domain.StatusCancelled,
+			Version:     3,
+		},
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	orders := testOrders()
+
+	maxCreated, err := writeSnapshot(path, orders)
+	if err != nil {
+		t.Fatalf("writeSnapshot() error = %v", err)
+	}
+	if want := orders[1].DateCreated; !maxCreated.Equal(want) {
+		t.Errorf("writeSnapshot() = %v, want %v", maxCreated, want)
+	}
+
+	got, since, err := readSnapshot(path)
+	if err != nil {
+		t.Fatalf("readSnapshot() error = %v", err)
+	}
+	if !since.Equal(maxCreated) {
+		t.Errorf("readSnapshot() since = %v, want %v", since, maxCreated)
+	}
+	if !reflect.DeepEqual(got, orders) {
+		t.Errorf("readSnapshot() = %+v, want %+v", got, orders)
+	}
+}
+
+func TestReadSnapshotCorrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, path string)
+	}{
+		{
+			name: "checksum mismatch",
+			corrupt: func(t *testing.T, path string) {
+				file, err := os.Create(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer file.Close()
+
+				payload := []byte("orders")
+				if err := gob.NewEncoder(file).Encode(snapshot{
+					Version:  snapshotVersion,
+					Count:    1,
+					Checksum: sha256.Sum256([]byte("other orders")),
+					Orders:   payload,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "truncated file",
+			corrupt: func(t *testing.T, path string) {
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Truncate(path, info.Size()/2); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "unsupported version",
+			corrupt: func(t *testing.T, path string) {
+				file, err := os.Create(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer file.Close()
+
+				if err := gob.NewEncoder(file).Encode(snapshot{Version: snapshotVersion - 1}); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.snapshot")
+			if _, err := writeSnapshot(path, testOrders()); err != nil {
+				t.Fatalf("writeSnapshot() error = %v", err)
+			}
+			tt.corrupt(t, path)
+
+			if _, _, err := readSnapshot(path); !errors.Is(err, ErrCorruptedSnapshot) {
+				t.Errorf("readSnapshot() error = %v, want %v", err, ErrCorruptedSnapshot)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import appLogger "github.com/Be1chenok/levelZero/logger"
+
+// tiered serves values from the local cache and falls back to the shared one,
+// values found in the shared cache are copied to the local one.
+type tiered[K comparable, V any] struct {
+	local  Cache[K, V]
+	shared Cache[K, V]
+	logger appLogger.Logger
+}
+
+func NewTiered[K comparable, V any](local, shared Cache[K, V], logger appLogger.Logger) Cache[K, V] {
+	return &tiered[K, V]{
+		local:  local,
+		shared: shared,
+		logger: logger,
+	}
+}
+
+func (c *tiered[K, V]) Get(key K) (V, bool) {
+	if value, ok := c.local.Get(key); ok {
+		return value, true
+	}
+
+	value, ok := c.shared.Get(key)
+	if !ok {
+		return value, false
+	}
+
+	// The value is served from the shared cache even if it cannot be copied.
+	if err := c.local.Set(key, value); err != nil {
+		c.logger.Errorf("failed to copy %v to the local cache: %v", key, err)
+	}
+
+	return value, true
+}
+
+func (c *tiered[K, V]) Set(key K, value V) error {
+	if err := c.shared.Set(key, value); err != nil {
+		return err
+	}
+
+	return c.local.Set(key, value)
+}
+
+// SetIfAbsent sets the value in the local cache only if it was absent from the shared one.
+func (c *tiered[K, V]) SetIfAbsent(key K, value V) (bool, error) {
+	set, err := c.shared.SetIfAbsent(key, value)
+	if err != nil || !set {
+		return false, err
+	}
+
+	return c.local.SetIfAbsent(key, value)
+}
+
+func (c *tiered[K, V]) Delete(key K) (bool, error) {
+	deleted, err := c.shared.Delete(key)
+	if err != nil {
+		return false, err
+	}
+
+	deletedLocal, err := c.local.Delete(key)
+
+	return deleted || deletedLocal, err
+}
+
+// DeletePrefix returns the number of keys removed from the shared cache.
+func (c *tiered[K, V]) DeletePrefix(prefix string) (int, error) {
+	deleted, err := c.shared.DeletePrefix(prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := c.local.DeletePrefix(prefix); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+func (c *tiered[K, V]) Flush() error {
+	if err := c.shared.Flush(); err != nil {
+		return err
+	}
+
+	return c.local.Flush()
+}
+
+// Stats reports the local cache, hits include the ones served by the shared cache.
+func (c *tiered[K, V]) Stats() Stats {
+	local, shared := c.local.Stats(), c.shared.Stats()
+
+	stats := local
+	stats.Hits += shared.Hits
+	stats.Misses = shared.Misses
+
+	return stats
+}
+
+// Range iterates over the local cache if it supports iteration.
+func (c *tiered[K, V]) Range(fn func(key K, value V) bool) {
+	if ranger, ok := c.local.(Ranger[K, V]); ok {
+		ranger.Range(fn)
+	}
+}
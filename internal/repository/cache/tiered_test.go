@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestTiered(t *testing.T) (Cache[string, testValue], Cache[string, testValue], Cache[string, testValue]) {
+	t.Helper()
+
+	local, err := New[string, testValue](config.CacheConfig{}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, shared := newTestRedis(t, "order:")
+
+	return NewTiered(local, shared, zap.NewNop().Sugar()), local, shared
+}
+
+func TestTieredGet(t *testing.T) {
+	cache, local, shared := newTestTiered(t)
+
+	want := testValue{Name: "a", Version: 1}
+	if err := shared.Set("a", want); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got, ok := cache.Get("a")
+	if !ok || got != want {
+		t.Fatalf("Get() = %+v, %v, want %+v, true", got, ok, want)
+	}
+
+	if got, ok := local.Get("a"); !ok || got != want {
+		t.Errorf("Get() did not copy the shared value to the local cache, got %+v, %v", got, ok)
+	}
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Get() of a missing key = ok")
+	}
+}
+
+func TestTieredGetLocalSetFails(t *testing.T) {
+	local, err := New[string, testValue](config.CacheConfig{MaxBytes: 1}, func(testValue) int64 { return 2 })
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, shared := newTestRedis(t, "order:")
+	core, logs := observer.New(zap.ErrorLevel)
+	cache := NewTiered(local, shared, zap.New(core).Sugar())
+
+	want := testValue{Name: "a", Version: 1}
+	if err := shared.Set("a", want); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if got, ok := cache.Get("a"); !ok || got != want {
+		t.Fatalf("Get() = %+v, %v, want %+v, true", got, ok, want)
+	}
+	if logs.Len() != 1 {
+		t.Errorf("logged %d errors, want 1", logs.Len())
+	}
+}
+
+func TestTieredSetIfAbsent(t *testing.T) {
+	tests := []struct {
+		name      string
+		shared    *testValue
+		wantSet   bool
+		wantLocal bool
+	}{
+		{name: "absent", wantSet: true, wantLocal: true},
+		{name: "present in shared cache", shared: &testValue{Name: "a", Version: 2}, wantSet: false, wantLocal: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, local, shared := newTestTiered(t)
+			if tt.shared != nil {
+				if err := shared.Set("a", *tt.shared); err != nil {
+					t.Fatalf("Set() error = %v", err)
+				}
+			}
+
+			set, err := cache.SetIfAbsent("a", testValue{Name: "a", Version: 1})
+			if err != nil {
+				t.Fatalf("SetIfAbsent() error = %v", err)
+			}
+			if set != tt.wantSet {
+				t.Errorf("SetIfAbsent() = %v, want %v", set, tt.wantSet)
+			}
+
+			if _, ok := local.Get("a"); ok != tt.wantLocal {
+				t.Errorf("local Get() = %v, want %v", ok, tt.wantLocal)
+			}
+
+			if tt.shared != nil {
+				if got, _ := shared.Get("a"); got != *tt.shared {
+					t.Errorf("shared Get() = %+v, want %+v", got, *tt.shared)
+				}
+			}
+		})
+	}
+}
+
+func TestTieredDelete(t *testing.T) {
+	cache, local, shared := newTestTiered(t)
+
+	for _, key := range []string{"a", "ab", "b"} {
+		if err := cache.Set(key, testValue{Name: key}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	deleted, err := cache.Delete("b")
+	if err != nil || !deleted {
+		t.Fatalf("Delete() = %v, %v, want true, nil", deleted, err)
+	}
+
+	count, err := cache.DeletePrefix("a")
+	if err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("DeletePrefix() = %d, want 2", count)
+	}
+
+	for _, c := range []Cache[string, testValue]{local, shared} {
+		if entries := c.Stats().Entries; entries != 0 {
+			t.Errorf("Stats().Entries = %d, want 0", entries)
+		}
+	}
+}
+
+func TestTieredStats(t *testing.T) {
+	cache, _, shared := newTestTiered(t)
+
+	if err := shared.Set("a", testValue{Name: "a"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("b")
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Stats() = %+v, want 2 hits, 1 miss and 1 entry", stats)
+	}
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+type Analytics interface {
+	Aggregate(ctx context.Context, query domain.AnalyticsQuery) ([]domain.AnalyticsRow, error)
+}
+
+type analytics struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepo(db *sql.DB) Analytics {
+	return &analytics{
+		db: db,
+	}
+}
+
+// dimensionKeys maps the dimensions to the grouped column, the sales are not grouped.
+var dimensionKeys = map[domain.AnalyticsDimension]string{
+	domain.DimensionSales:            "''::text",
+	domain.DimensionBrands:           "i.brand",
+	domain.DimensionProducts:         "i.nm_id::text",
+	domain.DimensionDeliveryServices: "o.delivery_service",
+	domain.DimensionRegions:          "d.region",
+	domain.DimensionCities:           "d.city",
+	domain.DimensionProviders:        "pay.provider",
+}
+
+// Aggregate groups the orders by bucket, dimension key and currency and keeps
+// the query limit of keys with the most orders, or items for item dimensions,
+// in every bucket.
+func (a analytics) Aggregate(ctx context.Context, query domain.AnalyticsQuery) ([]domain.AnalyticsRow, error) {
+	var report []domain.AnalyticsRow
+
+	key, ok := dimensionKeys[query.Dimension]
+	if !ok {
+		return nil, fmt.Errorf("unknown dimension %q", query.Dimension)
+	}
+
+	bucket := "NULL::timestamptz"
+	if query.Bucket != domain.BucketNone {
+		bucket = "date_trunc('" + string(query.Bucket) + "', o.date_created AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'"
+	}
+
+	from := " FROM orders o JOIN payments pay ON pay.order_uid = o.uid"
+	if query.Dimension == domain.DimensionRegions || query.Dimension == domain.DimensionCities {
+		from += " JOIN deliveries d ON d.order_uid = o.uid"
+	}
+
+	var orders, items, revenue, rank string
+	if query.Dimension.ByItem() {
+		from += " JOIN items i ON i.order_uid = o.uid"
+		orders, items, revenue = "COUNT(DISTINCT o.uid)", "COUNT(*)", "SUM(i.total_price)"
+		rank = items
+	} else {
+		from += " LEFT JOIN LATERAL (SELECT COUNT(*) AS items FROM items WHERE order_uid = o.uid) i ON true"
+		orders, items, revenue = "COUNT(*)", "SUM(i.items)", "SUM(pay.amount)"
+		rank = orders
+	}
+
+	where := &whereClause{}
+	if !query.From.IsZero() {
+		where.add("o.date_created >= ?", query.From.UTC())
+	}
+	if !query.To.IsZero() {
+		where.add("o.date_created < ?", query.To.UTC())
+	}
+	where.args = append(where.args, query.Limit)
+	limitParam := fmt.Sprintf("$%d", len(where.args))
+
+	rows, err := a.db.QueryContext(
+		ctx,
+		`SELECT bucket, key, currency, orders, items, revenue FROM (
+			SELECT
+			`+bucket+` AS bucket,
+			`+key+` AS key,
+			pay.currency AS currency,
+			`+orders+` AS orders,
+			COALESCE(`+items+`, 0) AS items,
+			`+revenue+` AS revenue,
+			ROW_NUMBER() OVER (PARTITION BY `+bucket+` ORDER BY `+rank+` DESC, `+key+`, pay.currency) AS rank`+
+			from+where.String()+`
+			GROUP BY 1, 2, 3
+		) ranked
+		WHERE rank <= `+limitParam+`
+		ORDER BY bucket, rank`,
+		where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row    domain.AnalyticsRow
+			bucket sql.NullTime
+		)
+		if err := rows.Scan(
+			&bucket,
+			&row.Key,
+			&row.Revenue.Currency,
+			&row.Orders,
+			&row.Items,
+			&row.Revenue.Amount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if bucket.Valid {
+			start := bucket.Time.UTC()
+			row.Bucket = &start
+		}
+		report = append(report, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return report, nil
+}
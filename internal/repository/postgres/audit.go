@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+func (o order) FindOrderEvents(ctx context.Context, orderUID string) ([]domain.OrderEvent, error) {
+	var events []domain.OrderEvent
+
+	rows, err := o.db.QueryContext(
+		ctx,
+		`SELECT
+		id,
+		order_uid,
+		type,
+		channel,
+		actor,
+		reference,
+		diff,
+		created_at
+		FROM order_events
+		WHERE order_uid=$1
+		ORDER BY id ASC`,
+		orderUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			event domain.OrderEvent
+			diff  []byte
+		)
+		if err := rows.Scan(
+			&event.ID,
+			&event.OrderUID,
+			&event.Type,
+			&event.Channel,
+			&event.Actor,
+			&event.Reference,
+			&diff,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if diff != nil {
+			if err := json.Unmarshal(diff, &event.Diff); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal diff: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// insertOrderEvent appends the event in the transaction of the change it records.
+func insertOrderEvent(ctx context.Context, tx *sql.Tx, event domain.OrderEvent) error {
+	// diff stays a nil interface for events without changes so that NULL is stored,
+	// an empty []byte would be sent as '' which is not valid jsonb.
+	var diff interface{}
+	if len(event.Diff) > 0 {
+		data, err := json.Marshal(event.Diff)
+		if err != nil {
+			return fmt.Errorf("failed to marshal diff: %w", err)
+		}
+		diff = data
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO order_events (
+		order_uid,
+		type,
+		channel,
+		actor,
+		reference,
+		diff,
+		created_at
+		) values ($1, $2, $3, $4, $5, $6, $7)`,
+		event.OrderUID,
+		event.Type,
+		event.Channel,
+		event.Actor,
+		event.Reference,
+		diff,
+		event.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert data into order_events table: %w", err)
+	}
+
+	return nil
+}
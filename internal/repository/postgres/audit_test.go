@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+func TestInsertOrderEventDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		diff     []domain.FieldChange
+		wantNull bool
+	}{
+		{name: "created event", wantNull: true},
+		{name: "empty diff", diff: []domain.FieldChange{}, wantNull: true},
+		{name: "updated event", diff: []domain.FieldChange{{Field: "locale", From: "en", To: "ru"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rd := newRecordDB(t)
+
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatalf("Begin() error = %v", err)
+			}
+			defer tx.Rollback()
+
+			event := domain.OrderEvent{
+				OrderUID:  "b563feb7b2b84b6test",
+				Type:      domain.EventCreated,
+				Source:    domain.Source{Channel: domain.ChannelBroker, Actor: "stan"},
+				Diff:      tt.diff,
+				CreatedAt: time.Now(),
+			}
+			if err := insertOrderEvent(context.Background(), tx, event); err != nil {
+				t.Fatalf("insertOrderEvent() error = %v", err)
+			}
+
+			if len(rd.execs) != 1 {
+				t.Fatalf("executed %d statements, want 1", len(rd.execs))
+			}
+			diff := rd.execs[0].args[5]
+			if (diff == nil) != tt.wantNull {
+				t.Errorf("diff = %q, want NULL = %v", diff, tt.wantNull)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+type Customer interface {
+	FindCustomerSummary(ctx context.Context, customerID string, topBrands int) (domain.CustomerSummary, error)
+}
+
+type customer struct {
+	db *sql.DB
+}
+
+func NewCustomerRepo(db *sql.DB) Customer {
+	return &customer{
+		db: db,
+	}
+}
+
+// FindCustomerSummary fails with domain.ErrNothingFound when the customer has no orders.
+func (c customer) FindCustomerSummary(ctx context.Context, customerID string, topBrands int) (domain.CustomerSummary, error) {
+	summary := domain.CustomerSummary{
+		CustomerID: customerID,
+	}
+
+	var firstOrderAt, lastOrderAt sql.NullTime
+	if err := c.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*), MIN(date_created), MAX(date_created)
+		FROM orders
+		WHERE customer_id = $1`,
+		customerID,
+	).Scan(&summary.Orders, &firstOrderAt, &lastOrderAt); err != nil {
+		return domain.CustomerSummary{}, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	if summary.Orders == 0 {
+		return domain.CustomerSummary{}, domain.ErrNothingFound
+	}
+	summary.FirstOrderAt = firstOrderAt.Time.UTC()
+	summary.LastOrderAt = lastOrderAt.Time.UTC()
+
+	var err error
+	if summary.LifetimeSpend, err = c.findSpend(ctx, customerID); err != nil {
+		return domain.CustomerSummary{}, err
+	}
+
+	if summary.TopBrands, err = c.findTopBrands(ctx, customerID, topBrands); err != nil {
+		return domain.CustomerSummary{}, err
+	}
+
+	if summary.Addresses, err = c.findAddresses(ctx, customerID); err != nil {
+		return domain.CustomerSummary{}, err
+	}
+
+	return summary, nil
+}
+
+func (c customer) findSpend(ctx context.Context, customerID string) ([]domain.Money, error) {
+	var spend []domain.Money
+
+	rows, err := c.db.QueryContext(
+		ctx,
+		`SELECT p.currency, SUM(p.amount)
+		FROM orders o
+		JOIN payments p ON p.order_uid = o.uid
+		WHERE o.customer_id = $1
+		GROUP BY p.currency
+		ORDER BY p.currency`,
+		customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var money domain.Money
+		if err := rows.Scan(&money.Currency, &money.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		spend = append(spend, money)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return spend, nil
+}
+
+func (c customer) findTopBrands(ctx context.Context, customerID string, limit int) ([]domain.BrandStat, error) {
+	var brands []domain.BrandStat
+
+	rows, err := c.db.QueryContext(
+		ctx,
+		`SELECT i.brand, COUNT(*)
+		FROM orders o
+		JOIN items i ON i.order_uid = o.uid
+		WHERE o.customer_id = $1
+		GROUP BY i.brand
+		ORDER BY COUNT(*) DESC, i.brand
+		LIMIT $2`,
+		customerID,
+		limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var brand domain.BrandStat
+		if err := rows.Scan(&brand.Brand, &brand.Items); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		brands = append(brands, brand)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return brands, nil
+}
+
+func (c customer) findAddresses(ctx context.Context, customerID string) ([]domain.AddressStat, error) {
+	var addresses []domain.AddressStat
+
+	rows, err := c.db.QueryContext(
+		ctx,
+		`SELECT d.region, d.city, d.address, d.zip, COUNT(*), MAX(o.date_created)
+		FROM orders o
+		JOIN deliveries d ON d.order_uid = o.uid
+		WHERE o.customer_id = $1
+		GROUP BY d.region, d.city, d.address, d.zip
+		ORDER BY MAX(o.date_created) DESC`,
+		customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var address domain.AddressStat
+		if err := rows.Scan(
+			&address.Region,
+			&address.City,
+			&address.Address,
+			&address.Zip,
+			&address.Orders,
+			&address.LastUsedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		address.LastUsedAt = address.LastUsedAt.UTC()
+		addresses = append(addresses, address)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return addresses, nil
+}
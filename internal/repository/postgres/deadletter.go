@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+type DeadLetter interface {
+	AddDeadLetter(ctx context.Context, deadLetter domain.DeadLetter) (int64, error)
+	FindDeadLetters(ctx context.Context, filter domain.DeadLetterFilter) ([]domain.DeadLetter, error)
+	FindDeadLetterByID(ctx context.Context, id int64) (domain.DeadLetter, error)
+	AddDeadLetterAttempt(ctx context.Context, id int64, reason string) error
+	MarkDeadLetterReplayed(ctx context.Context, id int64) error
+}
+
+type deadLetter struct {
+	db *sql.DB
+}
+
+func NewDeadLetterRepo(db *sql.DB) DeadLetter {
+	return &deadLetter{
+		db: db,
+	}
+}
+
+func (d deadLetter) AddDeadLetter(ctx context.Context, deadLetter domain.DeadLetter) (int64, error) {
+	var id int64
+
+	if err := d.db.QueryRowContext(
+		ctx,
+		`INSERT INTO dead_letters (
+		subject,
+		sequence,
+		payload,
+		error,
+		attempts,
+		first_failed_at,
+		last_failed_at
+		) values ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		deadLetter.Subject,
+		deadLetter.Sequence,
+		[]byte(deadLetter.Payload),
+		deadLetter.Error,
+		deadLetter.Attempts,
+		deadLetter.FirstFailedAt,
+		deadLetter.LastFailedAt,
+	).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert data into dead_letters table: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d deadLetter) FindDeadLetters(ctx context.Context, filter domain.DeadLetterFilter) ([]domain.DeadLetter, error) {
+	var deadLetters []domain.DeadLetter
+
+	rows, err := d.db.QueryContext(
+		ctx,
+		`SELECT
+		id,
+		subject,
+		sequence,
+		payload,
+		error,
+		attempts,
+		first_failed_at,
+		last_failed_at,
+		replayed_at
+		FROM dead_letters
+		WHERE NOT $1 OR replayed_at IS NULL
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`,
+		filter.Pending,
+		filter.Limit,
+		filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+func (d deadLetter) FindDeadLetterByID(ctx context.Context, id int64) (domain.DeadLetter, error) {
+	deadLetter, err := scanDeadLetter(d.db.QueryRowContext(
+		ctx,
+		`SELECT
+		id,
+		subject,
+		sequence,
+		payload,
+		error,
+		attempts,
+		first_failed_at,
+		last_failed_at,
+		replayed_at
+		FROM dead_letters
+		WHERE id=$1`,
+		id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DeadLetter{}, domain.ErrNothingFound
+		}
+		return domain.DeadLetter{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return deadLetter, nil
+}
+
+func (d deadLetter) AddDeadLetterAttempt(ctx context.Context, id int64, reason string) error {
+	result, err := d.db.ExecContext(
+		ctx,
+		`UPDATE dead_letters
+		SET attempts = attempts + 1, error = $2, last_failed_at = $3
+		WHERE id=$1`,
+		id,
+		reason,
+		time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to update dead_letters table: %w", err)
+	}
+
+	return checkRowsAffected(result)
+}
+
+func (d deadLetter) MarkDeadLetterReplayed(ctx context.Context, id int64) error {
+	result, err := d.db.ExecContext(
+		ctx,
+		`UPDATE dead_letters
+		SET replayed_at = $2
+		WHERE id=$1 AND replayed_at IS NULL`,
+		id,
+		time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to update dead_letters table: %w", err)
+	}
+
+	return checkRowsAffected(result)
+}
+
+func scanDeadLetter(row rowScanner) (domain.DeadLetter, error) {
+	var (
+		deadLetter domain.DeadLetter
+		payload    []byte
+		replayedAt sql.NullTime
+	)
+
+	if err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.Subject,
+		&deadLetter.Sequence,
+		&payload,
+		&deadLetter.Error,
+		&deadLetter.Attempts,
+		&deadLetter.FirstFailedAt,
+		&deadLetter.LastFailedAt,
+		&replayedAt,
+	); err != nil {
+		return domain.DeadLetter{}, err
+	}
+
+	deadLetter.Payload = string(payload)
+	if replayedAt.Valid {
+		deadLetter.ReplayedAt = &replayedAt.Time
+	}
+
+	return deadLetter, nil
+}
+
+func checkRowsAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.ErrNothingFound
+	}
+
+	return nil
+}
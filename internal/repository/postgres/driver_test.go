@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// recordDriver is a database/sql driver that records the statements it executes.
+type recordDriver struct {
+	mu    sync.Mutex
+	execs []recordedExec
+}
+
+type recordedExec struct {
+	query string
+	args  []driver.Value
+}
+
+var recordDriverID atomic.Int64
+
+func newRecordDB(t *testing.T) (*sql.DB, *recordDriver) {
+	t.Helper()
+
+	rd := &recordDriver{}
+	name := fmt.Sprintf("record%d", recordDriverID.Add(1))
+	sql.Register(name, rd)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, rd
+}
+
+func (d *recordDriver) Open(string) (driver.Conn, error) {
+	return &recordConn{driver: d}, nil
+}
+
+type recordConn struct {
+	driver *recordDriver
+}
+
+func (c *recordConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *recordConn) Close() error {
+	return nil
+}
+
+func (c *recordConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *recordConn) Commit() error {
+	return nil
+}
+
+func (c *recordConn) Rollback() error {
+	return nil
+}
+
+func (c *recordConn) Exec(query string, args []driver.Value) (driver.Result, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+
+	c.driver.execs = append(c.driver.execs, recordedExec{query: query, args: args})
+
+	return driver.RowsAffected(1), nil
+}
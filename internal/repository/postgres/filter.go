@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+type whereClause struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add appends a condition, each ? in it is replaced with the next positional parameter.
+func (w *whereClause) add(condition string, args ...interface{}) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		condition = strings.Replace(condition, "?", "$"+strconv.Itoa(len(w.args)), 1)
+	}
+
+	w.conditions = append(w.conditions, condition)
+}
+
+func (w *whereClause) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+func orderFilterClause(filter domain.OrderFilter) (string, *whereClause) {
+	from := " FROM orders o"
+	where := &whereClause{}
+
+	if filter.Provider != "" || filter.Bank != "" || filter.Currency != "" {
+		from += " JOIN payments p ON p.order_uid = o.uid"
+	}
+
+	if filter.CustomerID != "" {
+		where.add("o.customer_id = ?", filter.CustomerID)
+	}
+	if filter.TrackNumber != "" {
+		where.add("(o.track_number = ? OR EXISTS (SELECT 1 FROM items i WHERE i.order_uid = o.uid AND i.track_number = ?))",
+			filter.TrackNumber, filter.TrackNumber)
+	}
+	if filter.DeliveryService != "" {
+		where.add("o.delivery_service = ?", filter.DeliveryService)
+	}
+	if !filter.From.IsZero() {
+		where.add("o.date_created >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		where.add("o.date_created < ?", filter.To.UTC())
+	}
+	if filter.Provider != "" {
+		where.add("p.provider = ?", filter.Provider)
+	}
+	if filter.Bank != "" {
+		where.add("p.bank = ?", filter.Bank)
+	}
+	if filter.Currency != "" {
+		where.add("p.currency = ?", filter.Currency)
+	}
+	if filter.Brand != "" {
+		where.add("EXISTS (SELECT 1 FROM items i WHERE i.order_uid = o.uid AND i.brand = ?)", filter.Brand)
+	}
+	if filter.NmID != 0 {
+		where.add("EXISTS (SELECT 1 FROM items i WHERE i.order_uid = o.uid AND i.nm_id = ?)", filter.NmID)
+	}
+
+	return from, where
+}
+
+func keysetCondition(filter domain.OrderFilter) (string, []interface{}) {
+	operator := ">"
+	if filter.Desc {
+		operator = "<"
+	}
+
+	if filter.Sort == domain.SortByUID {
+		return "o.uid " + operator + " ?", []interface{}{filter.After.UID}
+	}
+
+	return "(o.date_created, o.uid) " + operator + " (?::timestamptz, ?)", []interface{}{filter.After.Value, filter.After.UID}
+}
+
+func orderByClause(filter domain.OrderFilter) string {
+	direction := " ASC"
+	if filter.Desc {
+		direction = " DESC"
+	}
+
+	if filter.Sort == domain.SortByUID {
+		return " ORDER BY o.uid" + direction
+	}
+
+	return " ORDER BY o.date_created" + direction + ", o.uid" + direction
+}
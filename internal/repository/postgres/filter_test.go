@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+func TestOrderFilterClause(t *testing.T) {
+	from := time.Date(2021, 11, 1, 0, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	tests := []struct {
+		name      string
+		filter    domain.OrderFilter
+		wantFrom  string
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:     "no filter",
+			wantFrom: " FROM orders o",
+		},
+		{
+			name:      "order columns",
+			filter:    domain.OrderFilter{CustomerID: "test", DeliveryService: "meest", From: from, To: from.AddDate(0, 1, 0)},
+			wantFrom:  " FROM orders o",
+			wantWhere: " WHERE o.customer_id = $1 AND o.delivery_service = $2 AND o.date_created >= $3 AND o.date_created < $4",
+			wantArgs:  []interface{}{"test", "meest", from.UTC(), from.AddDate(0, 1, 0).UTC()},
+		},
+		{
+			name:      "track number of the order or an item",
+			filter:    domain.OrderFilter{TrackNumber: "WBILMTESTTRACK"},
+			wantFrom:  " FROM orders o",
+			wantWhere: " WHERE (o.track_number = $1 OR EXISTS (SELECT 1 FROM items i WHERE i.order_uid = o.uid AND i.track_number = $2))",
+			wantArgs:  []interface{}{"WBILMTESTTRACK", "WBILMTESTTRACK"},
+		},
+		{
+			name:      "payment columns join payments",
+			filter:    domain.OrderFilter{Provider: "wbpay", Bank: "alpha", Currency: "USD"},
+			wantFrom:  " FROM orders o JOIN payments p ON p.order_uid = o.uid",
+			wantWhere: " WHERE p.provider = $1 AND p.bank = $2 AND p.currency = $3",
+			wantArgs:  []interface{}{"wbpay", "alpha", "USD"},
+		},
+		{
+			name:      "item columns",
+			filter:    domain.OrderFilter{Brand: "Vivienne Sabo", NmID: 2389212},
+			wantFrom:  " FROM orders o",
+			wantWhere: " WHERE EXISTS (SELECT 1 FROM items i WHERE i.order_uid = o.uid AND i.brand = $1) AND EXISTS (SELECT 1 FROM items i WHERE i.order_uid = o.uid AND i.nm_id = $2)",
+			wantArgs:  []interface{}{"Vivienne Sabo", 2389212},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFrom, where := orderFilterClause(tt.filter)
+			if gotFrom != tt.wantFrom {
+				t.Errorf("from = %q, want %q", gotFrom, tt.wantFrom)
+			}
+			if got := where.String(); got != tt.wantWhere {
+				t.Errorf("where = %q, want %q", got, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(where.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", where.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	cursor := &domain.Cursor{Value: "2021-11-26T06:22:19Z", UID: "b563feb7b2b84b6test"}
+
+	tests := []struct {
+		name        string
+		filter      domain.OrderFilter
+		wantWhere   string
+		wantArgs    []interface{}
+		wantOrderBy string
+	}{
+		{
+			name:        "date created descending",
+			filter:      domain.OrderFilter{Sort: domain.SortByDateCreated, Desc: true, After: cursor},
+			wantWhere:   " WHERE (o.date_created, o.uid) < ($1::timestamptz, $2)",
+			wantArgs:    []interface{}{cursor.Value, cursor.UID},
+			wantOrderBy: " ORDER BY o.date_created DESC, o.uid DESC",
+		},
+		{
+			name:        "date created ascending",
+			filter:      domain.OrderFilter{Sort: domain.SortByDateCreated, After: cursor},
+			wantWhere:   " WHERE (o.date_created, o.uid) > ($1::timestamptz, $2)",
+			wantArgs:    []interface{}{cursor.Value, cursor.UID},
+			wantOrderBy: " ORDER BY o.date_created ASC, o.uid ASC",
+		},
+		{
+			name:        "uid descending",
+			filter:      domain.OrderFilter{Sort: domain.SortByUID, Desc: true, After: cursor},
+			wantWhere:   " WHERE o.uid < $1",
+			wantArgs:    []interface{}{cursor.UID},
+			wantOrderBy: " ORDER BY o.uid DESC",
+		},
+		{
+			name:        "after the filter conditions",
+			filter:      domain.OrderFilter{CustomerID: "test", Sort: domain.SortByUID, After: cursor},
+			wantWhere:   " WHERE o.customer_id = $1 AND o.uid > $2",
+			wantArgs:    []interface{}{"test", cursor.UID},
+			wantOrderBy: " ORDER BY o.uid ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, where := orderFilterClause(tt.filter)
+			condition, args := keysetCondition(tt.filter)
+			where.add(condition, args...)
+
+			if got := where.String(); got != tt.wantWhere {
+				t.Errorf("where = %q, want %q", got, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(where.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", where.args, tt.wantArgs)
+			}
+			if got := orderByClause(tt.filter); got != tt.wantOrderBy {
+				t.Errorf("order by = %q, want %q", got, tt.wantOrderBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/lib/pq"
+)
+
+// CountOrders counts orders created at or after since, all of them when since is zero.
+func (o order) CountOrders(ctx context.Context, since time.Time) (int, error) {
+	var count int
+
+	if err := o.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM orders
+		WHERE $1::timestamptz IS NULL OR date_created >= $1`,
+		nullTime(since),
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	return count, nil
+}
+
+// LoadOrders walks over orders created at or after since, all of them when since
+// is zero, in batches of batchSize and passes each batch to load. Every batch
+// takes four queries whatever its size.
+func (o order) LoadOrders(ctx context.Context, since time.Time, batchSize int, load func(orders []domain.Order) error) error {
+	lastUID := ""
+
+	for {
+		orders, err := o.findOrdersAfter(ctx, since, lastUID, batchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(orders) == 0 {
+			return nil
+		}
+
+		if err := o.attachDetails(ctx, orders); err != nil {
+			return err
+		}
+
+		if err := load(orders); err != nil {
+			return err
+		}
+
+		if len(orders) < batchSize {
+			return nil
+		}
+
+		lastUID = orders[len(orders)-1].UID
+	}
+}
+
+func (o order) findOrdersAfter(ctx context.Context, since time.Time, lastUID string, limit int) ([]domain.Order, error) {
+	var orders []domain.Order
+
+	rows, err := o.db.QueryContext(
+		ctx,
+		`SELECT
+		uid,
+		track_number,
+		entry,
+		locale,
+		internal_signature,
+		customer_id,
+		delivery_service,
+		shardkey,
+		sm_id,
+		date_created,
+		oof_shard,
+		status,
+		version
+		FROM orders
+		WHERE uid > $1 AND ($3::timestamptz IS NULL OR date_created >= $3)
+		ORDER BY uid ASC
+		LIMIT $2`,
+		lastUID,
+		limit,
+		nullTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return orders, nil
+}
+
+// FindOrderVersions returns the versions of the stored orders among orderUIDs,
+// the missing ones are left out.
+func (o order) FindOrderVersions(ctx context.Context, orderUIDs []string) (map[string]int64, error) {
+	versions := make(map[string]int64, len(orderUIDs))
+
+	rows, err := o.db.QueryContext(
+		ctx,
+		`SELECT uid, version FROM orders WHERE uid = ANY($1)`,
+		pq.Array(orderUIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderUID string
+			version  int64
+		)
+		if err := rows.Scan(&orderUID, &version); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		versions[orderUID] = version
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return versions, nil
+}
+
+// attachDetails fills deliveries, payments and items of the orders
+// with one query per table.
+func (o order) attachDetails(ctx context.Context, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	uids := make([]string, 0, len(orders))
+	index := make(map[string]int, len(orders))
+	for idx, order := range orders {
+		uids = append(uids, order.UID)
+		index[order.UID] = idx
+	}
+
+	if err := o.attachDeliveries(ctx, orders, uids, index); err != nil {
+		return fmt.Errorf("failed to find deliveries by orderUIDs: %w", err)
+	}
+
+	if err := o.attachPayments(ctx, orders, uids, index); err != nil {
+		return fmt.Errorf("failed to find payments by orderUIDs: %w", err)
+	}
+
+	if err := o.attachItems(ctx, orders, uids, index); err != nil {
+		return fmt.Errorf("failed to find items by orderUIDs: %w", err)
+	}
+
+	return nil
+}
+
+func (o order) attachDeliveries(ctx context.Context, orders []domain.Order, uids []string, index map[string]int) error {
+	rows, err := o.db.QueryContext(
+		ctx,
+		`SELECT
+		order_uid,
+		name,
+		phone,
+		zip,
+		city,
+		address,
+		region,
+		email
+		FROM deliveries
+		WHERE order_uid = ANY($1)`,
+		pq.Array(uids))
+	if err != nil {
+		return fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderUID string
+			delivery domain.Delivery
+		)
+		if err := rows.Scan(
+			&orderUID,
+			&delivery.Name,
+			&delivery.Phone,
+			&delivery.Zip,
+			&delivery.City,
+			&delivery.Address,
+			&delivery.Region,
+			&delivery.Email,
+		); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		orders[index[orderUID]].Delivery = delivery
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return nil
+}
+
+func (o order) attachPayments(ctx context.Context, orders []domain.Order, uids []string, index map[string]int) error {
+	rows, err := o.db.QueryContext(
+		ctx,
+		`SELECT
+		order_uid,
+		transaction,
+		request_id,
+		currency,
+		provider,
+		amount,
+		payment_dt,
+		bank,
+		delivery_cost,
+		goods_total,
+		custom_fee
+		FROM payments
+		WHERE order_uid = ANY($1)`,
+		pq.Array(uids))
+	if err != nil {
+		return fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderUID string
+			payment  domain.Payment
+		)
+		if err := rows.Scan(
+			&orderUID,
+			&payment.Transaction,
+			&payment.RequestID,
+			&payment.Currency,
+			&payment.Provider,
+			&payment.Amount,
+			&payment.PaymentDT,
+			&payment.Bank,
+			&payment.DeliveryCost,
+			&payment.GoodsTotal,
+			&payment.CustomFee,
+		); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		orders[index[orderUID]].Payment = payment
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return nil
+}
+
+func (o order) attachItems(ctx context.Context, orders []domain.Order, uids []string, index map[string]int) error {
+	rows, err := o.db.QueryContext(
+		ctx,
+		`SELECT
+		order_uid,
+		chrt_id,
+		track_number,
+		price,
+		rid,
+		name,
+		sale,
+		size,
+		total_price,
+		nm_id,
+		brand,
+		status
+		FROM items
+		WHERE order_uid = ANY($1)
+		ORDER BY id ASC`,
+		pq.Array(uids))
+	if err != nil {
+		return fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderUID string
+			item     domain.Item
+		)
+		if err := rows.Scan(
+			&orderUID,
+			&item.ChrtID,
+			&item.TrackNumber,
+			&item.Price,
+			&item.RID,
+			&item.Name,
+			&item.Sale,
+			&item.Size,
+			&item.TotalPrice,
+			&item.NmID,
+			&item.Brand,
+			&item.Status,
+		); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		idx := index[orderUID]
+		orders[idx].Items = append(orders[idx].Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return nil
+}
+
+func nullTime(value time.Time) sql.NullTime {
+	return sql.NullTime{
+		Time:  value,
+		Valid: !value.IsZero(),
+	}
+}
+
+func scanOrder(row rowScanner) (domain.Order, error) {
+	var order domain.Order
+
+	if err := row.Scan(
+		&order.UID,
+		&order.TrackNumber,
+		&order.Entry,
+		&order.Locale,
+		&order.InternalSignature,
+		&order.CustomerID,
+		&order.DeliveryService,
+		&order.ShardKey,
+		&order.SmID,
+		&order.DateCreated,
+		&order.OofShard,
+		&order.Status,
+		&order.Version,
+	); err != nil {
+		return domain.Order{}, err
+	}
+	order.DateCreated = order.DateCreated.UTC()
+
+	return order, nil
+}
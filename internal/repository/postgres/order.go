@@ -3,18 +3,29 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/Be1chenok/levelZero/internal/domain"
 )
 
 type Order interface {
-	AddOrder(ctx context.Context, order domain.Order) error
-	FindAllOrders(ctx context.Context) ([]domain.Order, error)
+	AddOrder(ctx context.Context, order domain.Order, event domain.OrderEvent) error
+	UpdateOrder(ctx context.Context, order domain.Order, version int64, event domain.OrderEvent) error
+	DeleteOrder(ctx context.Context, orderUID string, version int64, event domain.OrderEvent) error
+	CountOrders(ctx context.Context, since time.Time) (int, error)
+	LoadOrders(ctx context.Context, since time.Time, batchSize int, load func(orders []domain.Order) error) error
+	FindOrderVersions(ctx context.Context, orderUIDs []string) (map[string]int64, error)
+	FindOrders(ctx context.Context, filter domain.OrderFilter) (domain.OrderPage, error)
 	FindOrderByUID(ctx context.Context, orderUID string) (domain.Order, error)
 	FindDeliveryByOrderUID(ctx context.Context, orderUID string) (domain.Delivery, error)
 	FindPaymentByOrderUID(ctx context.Context, orderUID string) (domain.Payment, error)
 	FindItemsByOrderUID(ctx context.Context, orderUID string) ([]domain.Item, error)
+	ChangeOrderStatus(ctx context.Context, change domain.StatusChange, version int64, event domain.OrderEvent) error
+	FindStatusHistory(ctx context.Context, orderUID string) ([]domain.StatusChange, error)
+	FindOrderEvents(ctx context.Context, orderUID string) ([]domain.OrderEvent, error)
 }
 
 type order struct {
@@ -39,7 +50,7 @@ func wrapRollbackError(err, e error) error {
 	return fmt.Errorf("rollback tx: %w:%w", err, e)
 }
 
-func (o order) AddOrder(ctx context.Context, order domain.Order) (err error) {
+func (o order) AddOrder(ctx context.Context, order domain.Order, event domain.OrderEvent) (err error) {
 	tx, err := o.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to open transaction: %w", err)
@@ -73,8 +84,10 @@ func (o order) AddOrder(ctx context.Context, order domain.Order) (err error) {
 		shardkey,
 		sm_id,
 		date_created,
-		oof_shard
-		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		oof_shard,
+		status,
+		version
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
 		order.UID,
 		order.TrackNumber,
 		order.Entry,
@@ -86,7 +99,12 @@ func (o order) AddOrder(ctx context.Context, order domain.Order) (err error) {
 		order.SmID,
 		order.DateCreated,
 		order.OofShard,
+		order.Status,
+		order.Version,
 	); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("failed to insert data into orders table: %w", domain.ErrAlreadyExists)
+		}
 		return fmt.Errorf("failed to insert data into orders table: %w", err)
 	}
 
@@ -144,6 +162,212 @@ func (o order) AddOrder(ctx context.Context, order domain.Order) (err error) {
 		return fmt.Errorf("failed to insert data into payments table: %w", err)
 	}
 
+	if err := insertItems(ctx, tx, order.UID, order.Items); err != nil {
+		return err
+	}
+
+	if err := insertStatusChange(ctx, tx, domain.StatusChange{
+		OrderUID:  order.UID,
+		To:        order.Status,
+		ChangedAt: event.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	if err := insertOrderEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateOrder replaces the order if it is still at version and moves it
+// to the next version, otherwise it fails with domain.ErrVersionConflict.
+func (o order) UpdateOrder(ctx context.Context, order domain.Order, version int64, event domain.OrderEvent) (err error) {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				err = wrapRollbackError(err, e)
+
+				return
+			}
+
+			return
+		}
+
+		if e := tx.Commit(); e != nil {
+			err = wrapCommitError(err, e)
+		}
+	}()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE orders SET
+		track_number = $2,
+		entry = $3,
+		locale = $4,
+		internal_signature = $5,
+		customer_id = $6,
+		delivery_service = $7,
+		shardkey = $8,
+		sm_id = $9,
+		date_created = $10,
+		oof_shard = $11,
+		version = version + 1
+		WHERE uid=$1 AND version=$12`,
+		order.UID,
+		order.TrackNumber,
+		order.Entry,
+		order.Locale,
+		order.InternalSignature,
+		order.CustomerID,
+		order.DeliveryService,
+		order.ShardKey,
+		order.SmID,
+		order.DateCreated,
+		order.OofShard,
+		version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update orders table: %w", err)
+	}
+
+	if err := checkVersion(result); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE deliveries SET
+		name = $2,
+		phone = $3,
+		zip = $4,
+		city = $5,
+		address = $6,
+		region = $7,
+		email = $8
+		WHERE order_uid=$1`,
+		order.UID,
+		order.Delivery.Name,
+		order.Delivery.Phone,
+		order.Delivery.Zip,
+		order.Delivery.City,
+		order.Delivery.Address,
+		order.Delivery.Region,
+		order.Delivery.Email,
+	); err != nil {
+		return fmt.Errorf("failed to update deliveries table: %w", err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE payments SET
+		transaction = $2,
+		request_id = $3,
+		currency = $4,
+		provider = $5,
+		amount = $6,
+		payment_dt = $7,
+		bank = $8,
+		delivery_cost = $9,
+		goods_total = $10,
+		custom_fee = $11
+		WHERE order_uid=$1`,
+		order.UID,
+		order.Payment.Transaction,
+		order.Payment.RequestID,
+		order.Payment.Currency,
+		order.Payment.Provider,
+		order.Payment.Amount,
+		order.Payment.PaymentDT,
+		order.Payment.Bank,
+		order.Payment.DeliveryCost,
+		order.Payment.GoodsTotal,
+		order.Payment.CustomFee,
+	); err != nil {
+		return fmt.Errorf("failed to update payments table: %w", err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM items WHERE order_uid=$1`,
+		order.UID,
+	); err != nil {
+		return fmt.Errorf("failed to delete data from items table: %w", err)
+	}
+
+	if err := insertItems(ctx, tx, order.UID, order.Items); err != nil {
+		return err
+	}
+
+	if err := insertOrderEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteOrder deletes the order with its details if it is still at version,
+// otherwise it fails with domain.ErrVersionConflict. The events of the order are kept.
+func (o order) DeleteOrder(ctx context.Context, orderUID string, version int64, event domain.OrderEvent) (err error) {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				err = wrapRollbackError(err, e)
+
+				return
+			}
+
+			return
+		}
+
+		if e := tx.Commit(); e != nil {
+			err = wrapCommitError(err, e)
+		}
+	}()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`DELETE FROM orders WHERE uid=$1 AND version=$2`,
+		orderUID,
+		version)
+	if err != nil {
+		return fmt.Errorf("failed to delete data from orders table: %w", err)
+	}
+
+	if err := checkVersion(result); err != nil {
+		return err
+	}
+
+	if err := insertOrderEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkVersion reports domain.ErrVersionConflict when the statement
+// has not found the order at the expected version.
+func checkVersion(result sql.Result) error {
+	if err := checkRowsAffected(result); err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			return domain.ErrVersionConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+func insertItems(ctx context.Context, tx *sql.Tx, orderUID string, items []domain.Item) error {
 	stmt, err := tx.PrepareContext(
 		ctx,
 		`INSERT INTO items (
@@ -165,10 +389,10 @@ func (o order) AddOrder(ctx context.Context, order domain.Order) (err error) {
 	}
 	defer stmt.Close()
 
-	for _, item := range order.Items {
+	for _, item := range items {
 		if _, err := stmt.ExecContext(
 			ctx,
-			order.UID,
+			orderUID,
 			item.ChrtID,
 			item.TrackNumber,
 			item.Price,
@@ -188,75 +412,71 @@ func (o order) AddOrder(ctx context.Context, order domain.Order) (err error) {
 	return nil
 }
 
-func (o order) FindAllOrders(ctx context.Context) ([]domain.Order, error) {
-	var orders []domain.Order
+func (o order) FindOrders(ctx context.Context, filter domain.OrderFilter) (domain.OrderPage, error) {
+	var page domain.OrderPage
+
+	from, where := orderFilterClause(filter)
+
+	if err := o.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*)`+from+where.String(),
+		where.args...,
+	).Scan(&page.Total); err != nil {
+		return domain.OrderPage{}, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	if filter.After != nil {
+		condition, args := keysetCondition(filter)
+		where.add(condition, args...)
+	}
+
 	rows, err := o.db.QueryContext(
 		ctx,
 		`SELECT
-		uid,
-		track_number,
-		entry,
-		locale,
-		internal_signature,
-		customer_id,
-		delivery_service,
-		shardkey,
-		sm_id,
-		date_created,
-		oof_shard
-		FROM orders`)
+		o.uid,
+		o.track_number,
+		o.entry,
+		o.locale,
+		o.internal_signature,
+		o.customer_id,
+		o.delivery_service,
+		o.shardkey,
+		o.sm_id,
+		o.date_created,
+		o.oof_shard,
+		o.status,
+		o.version`+from+where.String()+orderByClause(filter)+
+			` LIMIT `+strconv.Itoa(filter.Limit+1),
+		where.args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query rows: %w", err)
+		return domain.OrderPage{}, fmt.Errorf("failed to query rows: %w", err)
 	}
 
 	defer rows.Close()
 
 	for rows.Next() {
-		var order domain.Order
-		if err := rows.Scan(
-			&order.UID,
-			&order.TrackNumber,
-			&order.Entry,
-			&order.Locale,
-			&order.InternalSignature,
-			&order.CustomerID,
-			&order.DeliveryService,
-			&order.ShardKey,
-			&order.SmID,
-			&order.DateCreated,
-			&order.OofShard,
-		); err != nil {
-			return nil, domain.ErrNothingFound
+		order, err := scanOrder(rows)
+		if err != nil {
+			return domain.OrderPage{}, fmt.Errorf("failed to scan row: %w", err)
 		}
-		orders = append(orders, order)
+		page.Orders = append(page.Orders, order)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+		return domain.OrderPage{}, fmt.Errorf("failed to iterating over rows: %w", err)
 	}
 
-	for idx := range orders {
-		delivery, err := o.FindDeliveryByOrderUID(ctx, orders[idx].UID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find deliveries by orderUID: %w", err)
-		}
-
-		payment, err := o.FindPaymentByOrderUID(ctx, orders[idx].UID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find payments by orderUID: %w", err)
-		}
-
-		items, err := o.FindItemsByOrderUID(ctx, orders[idx].UID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find items by orderUID: %w", err)
-		}
+	if len(page.Orders) > filter.Limit {
+		page.Orders = page.Orders[:filter.Limit]
+		last := page.Orders[len(page.Orders)-1]
+		page.NextCursor = domain.Cursor{Value: last.DateCreated.Format(time.RFC3339Nano), UID: last.UID}.Encode()
+	}
 
-		orders[idx].Delivery = delivery
-		orders[idx].Payment = payment
-		orders[idx].Items = items
+	if err := o.attachDetails(ctx, page.Orders); err != nil {
+		return domain.OrderPage{}, err
 	}
 
-	return orders, nil
+	return page, nil
 }
 
 func (o order) FindOrderByUID(ctx context.Context, orderUID string) (domain.Order, error) {
@@ -275,7 +495,9 @@ func (o order) FindOrderByUID(ctx context.Context, orderUID string) (domain.Orde
 		shardkey,
 		sm_id,
 		date_created,
-		oof_shard
+		oof_shard,
+		status,
+		version
 		FROM orders
 		WHERE uid=$1`,
 		orderUID).Scan(
@@ -290,9 +512,15 @@ func (o order) FindOrderByUID(ctx context.Context, orderUID string) (domain.Orde
 		&order.SmID,
 		&order.DateCreated,
 		&order.OofShard,
+		&order.Status,
+		&order.Version,
 	); err != nil {
-		return domain.Order{}, domain.ErrNothingFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Order{}, domain.ErrNothingFound
+		}
+		return domain.Order{}, fmt.Errorf("failed to scan row: %w", err)
 	}
+	order.DateCreated = order.DateCreated.UTC()
 
 	return order, nil
 }
@@ -321,7 +549,10 @@ func (o order) FindDeliveryByOrderUID(ctx context.Context, orderUID string) (dom
 		&delivery.Region,
 		&delivery.Email,
 	); err != nil {
-		return domain.Delivery{}, domain.ErrNothingFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Delivery{}, domain.ErrNothingFound
+		}
+		return domain.Delivery{}, fmt.Errorf("failed to scan row: %w", err)
 	}
 
 	return delivery, nil
@@ -357,7 +588,10 @@ func (o order) FindPaymentByOrderUID(ctx context.Context, orderUID string) (doma
 		&payment.GoodsTotal,
 		&payment.CustomFee,
 	); err != nil {
-		return domain.Payment{}, domain.ErrNothingFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Payment{}, domain.ErrNothingFound
+		}
+		return domain.Payment{}, fmt.Errorf("failed to scan row: %w", err)
 	}
 
 	return payment, nil
@@ -404,7 +638,7 @@ func (o order) FindItemsByOrderUID(ctx context.Context, orderUID string) ([]doma
 			&item.NmID,
 			&item.Brand,
 			&item.Status); err != nil {
-			return nil, domain.ErrNothingFound
+			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		items = append(items, item)
 	}
@@ -413,5 +647,9 @@ func (o order) FindItemsByOrderUID(ctx context.Context, orderUID string) ([]doma
 		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
 	}
 
+	if len(items) == 0 {
+		return nil, domain.ErrNothingFound
+	}
+
 	return items, nil
 }
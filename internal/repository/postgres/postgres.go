@@ -9,6 +9,10 @@ import (
 	_ "github.com/lib/pq"
 )
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 func New(conf *config.Config, ctx context.Context) (*sql.DB, error) {
 	db, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		conf.Postgres.Host,
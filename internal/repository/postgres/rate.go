@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+type Rate interface {
+	AddRates(ctx context.Context, rates []domain.ExchangeRate) error
+	FindRates(ctx context.Context, filter domain.RateFilter) ([]domain.ExchangeRate, error)
+	FindRate(ctx context.Context, currency, base string, at time.Time) (domain.ExchangeRate, error)
+	SumOrders(ctx context.Context, filter domain.OrderFilter, base string) ([]domain.CurrencyTotal, error)
+}
+
+type rate struct {
+	db *sql.DB
+}
+
+func NewRateRepo(db *sql.DB) Rate {
+	return &rate{
+		db: db,
+	}
+}
+
+// AddRates stores the rates, a rate of a pair at the same time replaces the stored one.
+func (r rate) AddRates(ctx context.Context, rates []domain.ExchangeRate) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				err = wrapRollbackError(err, e)
+
+				return
+			}
+
+			return
+		}
+
+		if e := tx.Commit(); e != nil {
+			err = wrapCommitError(err, e)
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(
+		ctx,
+		`INSERT INTO exchange_rates (
+		currency,
+		base,
+		rate,
+		effective_at
+		) values ($1, $2, $3, $4)
+		ON CONFLICT (currency, base, effective_at) DO UPDATE SET rate = EXCLUDED.rate`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, rate := range rates {
+		if _, err := stmt.ExecContext(ctx, rate.Currency, rate.Base, rate.Rate.String(), rate.EffectiveAt); err != nil {
+			return fmt.Errorf("failed to insert data into exchange_rates table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r rate) FindRates(ctx context.Context, filter domain.RateFilter) ([]domain.ExchangeRate, error) {
+	rates := make([]domain.ExchangeRate, 0)
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT
+		currency,
+		base,
+		rate,
+		effective_at
+		FROM exchange_rates
+		WHERE ($1 = '' OR currency = $1) AND ($2 = '' OR base = $2)
+		ORDER BY currency, base, effective_at DESC`,
+		filter.Currency,
+		filter.Base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		rate, err := scanRate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return rates, nil
+}
+
+// FindRate returns the rate of currency to base effective at the time, or the
+// rate of base to currency when it is the later one. It fails with
+// domain.ErrRateNotFound when neither is effective.
+func (r rate) FindRate(ctx context.Context, currency, base string, at time.Time) (domain.ExchangeRate, error) {
+	rate, err := scanRate(r.db.QueryRowContext(
+		ctx,
+		`SELECT
+		currency,
+		base,
+		rate,
+		effective_at
+		FROM exchange_rates
+		WHERE ((currency = $1 AND base = $2) OR (currency = $2 AND base = $1)) AND effective_at <= $3
+		ORDER BY effective_at DESC
+		LIMIT 1`,
+		currency,
+		base,
+		at))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ExchangeRate{}, domain.ErrRateNotFound
+		}
+		return domain.ExchangeRate{}, fmt.Errorf("failed to find exchange rate: %w", err)
+	}
+
+	if rate.Currency != currency {
+		return rate.Invert(), nil
+	}
+
+	return rate, nil
+}
+
+// SumOrders sums the payments of the filtered orders by their currency and the
+// rate to base effective when each order was created. The rate is inverted
+// after the sums, as a rate of the opposite pair may not be a finite decimal.
+func (r rate) SumOrders(ctx context.Context, filter domain.OrderFilter, base string) ([]domain.CurrencyTotal, error) {
+	var totals []domain.CurrencyTotal
+
+	from, where := orderFilterClause(filter)
+	where.args = append(where.args, base)
+	baseParam := fmt.Sprintf("$%d", len(where.args))
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT
+		pay.currency,
+		er.currency,
+		er.base,
+		er.rate,
+		COUNT(*),
+		SUM(pay.amount),
+		SUM(pay.delivery_cost),
+		SUM(pay.goods_total),
+		SUM(pay.custom_fee)`+from+`
+		JOIN payments pay ON pay.order_uid = o.uid
+		LEFT JOIN LATERAL (
+			SELECT currency, base, rate
+			FROM exchange_rates
+			WHERE ((currency = pay.currency AND base = `+baseParam+`) OR (currency = `+baseParam+` AND base = pay.currency))
+			AND effective_at <= o.date_created
+			ORDER BY effective_at DESC
+			LIMIT 1
+		) er ON true`+where.String()+`
+		GROUP BY pay.currency, er.currency, er.base, er.rate
+		ORDER BY pay.currency`,
+		where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			total                             domain.CurrencyTotal
+			rateCurrency, rateBase, rateValue sql.NullString
+		)
+		if err := rows.Scan(
+			&total.Currency,
+			&rateCurrency,
+			&rateBase,
+			&rateValue,
+			&total.Orders,
+			&total.Amount,
+			&total.DeliveryCost,
+			&total.GoodsTotal,
+			&total.CustomFee,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if rateValue.Valid {
+			value, err := domain.ParseDecimal(rateValue.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse rate: %w", err)
+			}
+
+			rate := domain.ExchangeRate{Currency: rateCurrency.String, Base: rateBase.String, Rate: value}
+			if rate.Currency != total.Currency {
+				rate = rate.Invert()
+			}
+			total.Rate = &rate
+		}
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return totals, nil
+}
+
+func scanRate(row rowScanner) (domain.ExchangeRate, error) {
+	var (
+		rate  domain.ExchangeRate
+		value string
+	)
+
+	if err := row.Scan(
+		&rate.Currency,
+		&rate.Base,
+		&value,
+		&rate.EffectiveAt,
+	); err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	rate.EffectiveAt = rate.EffectiveAt.UTC()
+
+	var err error
+	if rate.Rate, err = domain.ParseDecimal(value); err != nil {
+		return domain.ExchangeRate{}, fmt.Errorf("failed to parse rate: %w", err)
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+// ChangeOrderStatus moves the order from change.From to change.To and to the
+// next version, and records the transition and the event. It fails with
+// domain.ErrVersionConflict when the order is no longer at version.
+func (o order) ChangeOrderStatus(ctx context.Context, change domain.StatusChange, version int64, event domain.OrderEvent) (err error) {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				err = wrapRollbackError(err, e)
+
+				return
+			}
+
+			return
+		}
+
+		if e := tx.Commit(); e != nil {
+			err = wrapCommitError(err, e)
+		}
+	}()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE orders SET status = $3, version = version + 1
+		WHERE uid=$1 AND status=$2 AND version=$4`,
+		change.OrderUID,
+		change.From,
+		change.To,
+		version)
+	if err != nil {
+		return fmt.Errorf("failed to update orders table: %w", err)
+	}
+
+	if err := checkVersion(result); err != nil {
+		return err
+	}
+
+	if err := insertStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if err := insertOrderEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o order) FindStatusHistory(ctx context.Context, orderUID string) ([]domain.StatusChange, error) {
+	var history []domain.StatusChange
+
+	rows, err := o.db.QueryContext(
+		ctx,
+		`SELECT
+		order_uid,
+		from_status,
+		to_status,
+		changed_at
+		FROM order_status_history
+		WHERE order_uid=$1
+		ORDER BY id ASC`,
+		orderUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			change domain.StatusChange
+			from   sql.NullString
+		)
+		if err := rows.Scan(
+			&change.OrderUID,
+			&from,
+			&change.To,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		change.From = domain.OrderStatus(from.String)
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterating over rows: %w", err)
+	}
+
+	return history, nil
+}
+
+func insertStatusChange(ctx context.Context, tx *sql.Tx, change domain.StatusChange) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO order_status_history (
+		order_uid,
+		from_status,
+		to_status,
+		changed_at
+		) values ($1, $2, $3, $4)`,
+		change.OrderUID,
+		sql.NullString{String: string(change.From), Valid: change.From != ""},
+		change.To,
+		change.ChangedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert data into order_status_history table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+func New(conf *config.Config, ctx context.Context) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         conf.Redis.Host + ":" + strconv.Itoa(conf.Redis.Port),
+		Password:     conf.Redis.Password,
+		DB:           conf.Redis.DB,
+		ReadTimeout:  conf.Redis.Timeout,
+		WriteTimeout: conf.Redis.Timeout,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return client, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 
 	"github.com/Be1chenok/levelZero/internal/config"
 	"github.com/Be1chenok/levelZero/internal/repository/broker"
@@ -9,21 +10,33 @@ import (
 	"github.com/Be1chenok/levelZero/internal/repository/postgres"
 	appLogger "github.com/Be1chenok/levelZero/logger"
 	"github.com/nats-io/stan.go"
+	"github.com/redis/go-redis/v9"
 )
 
 type Repository struct {
-	Broker        broker.Subscriber
-	PostgresOrder postgres.Order
-	CacheOrder    cache.Cache
+	PostgresOrder      postgres.Order
+	PostgresDeadLetter postgres.DeadLetter
+	PostgresRate       postgres.Rate
+	PostgresAnalytics  postgres.Analytics
+	PostgresCustomer   postgres.Customer
+	CacheOrder         cache.Order
+	BrokerEvents       broker.Events
 }
 
-func New(conf *config.Config, logger appLogger.Logger, db *sql.DB, sc stan.Conn) *Repository {
+func New(conf *config.Config, logger appLogger.Logger, db *sql.DB, redisClient *redis.Client, sc stan.Conn) (*Repository, error) {
 	postgresOrder := postgres.NewOrderRepo(db)
-	cacheOrder := cache.New(postgresOrder, logger)
+	cacheOrder, err := cache.NewOrder(conf, postgresOrder, redisClient, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache: %w", err)
+	}
 
 	return &Repository{
-		Broker:        broker.NewSubscriber(conf, logger, sc, postgresOrder, cacheOrder),
-		PostgresOrder: postgresOrder,
-		CacheOrder:    cacheOrder,
-	}
+		PostgresOrder:      postgresOrder,
+		PostgresDeadLetter: postgres.NewDeadLetterRepo(db),
+		PostgresRate:       postgres.NewRateRepo(db),
+		PostgresAnalytics:  postgres.NewAnalyticsRepo(db),
+		PostgresCustomer:   postgres.NewCustomerRepo(db),
+		CacheOrder:         cacheOrder,
+		BrokerEvents:       broker.NewEvents(conf, sc.NatsConn()),
+	}, nil
 }
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+)
+
+type Analytics interface {
+	Report(ctx context.Context, query domain.AnalyticsQuery) ([]domain.AnalyticsRow, error)
+}
+
+type analytics struct {
+	postgresAnalytics postgres.Analytics
+}
+
+func NewAnalytics(postgresAnalytics postgres.Analytics) Analytics {
+	return &analytics{
+		postgresAnalytics: postgresAnalytics,
+	}
+}
+
+func (a analytics) Report(ctx context.Context, query domain.AnalyticsQuery) ([]domain.AnalyticsRow, error) {
+	report, err := a.postgresAnalytics.Aggregate(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate orders: %w", err)
+	}
+
+	for idx, row := range report {
+		average := domain.NewMoney(0, row.Revenue.Currency)
+		if row.Orders > 0 {
+			average.Amount = int64(math.Round(float64(row.Revenue.Amount) / float64(row.Orders)))
+		}
+		report[idx].AverageBasket = average
+	}
+
+	return report, nil
+}
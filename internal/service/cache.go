@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/cache"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+	appLogger "github.com/Be1chenok/levelZero/logger"
+	"go.uber.org/zap"
+)
+
+type Cache interface {
+	Evict(orderUID string) (bool, error)
+	Invalidate(orderUID string) error
+	EvictPrefix(prefix string) (int, error)
+	Flush() error
+	Reload(ctx context.Context, orderUID string) (domain.Order, error)
+	Stats() cache.Stats
+}
+
+type cacheAdmin struct {
+	postgresOrder postgres.Order
+	cacheOrder    cache.Order
+	logger        appLogger.Logger
+}
+
+func NewCache(postgresOrder postgres.Order, cacheOrder cache.Order, logger appLogger.Logger) Cache {
+	return &cacheAdmin{
+		postgresOrder: postgresOrder,
+		cacheOrder:    cacheOrder,
+		logger:        logger.With(zap.String("component", "service-cache")),
+	}
+}
+
+func (c cacheAdmin) Evict(orderUID string) (bool, error) {
+	evicted, err := c.cacheOrder.Delete(orderUID)
+	if err != nil {
+		return false, fmt.Errorf("failed to evict order: %w", err)
+	}
+
+	if evicted {
+		c.logger.Infof("order %s evicted from cache", orderUID)
+	}
+
+	return evicted, nil
+}
+
+// Invalidate drops the order changed by another instance from the local cache.
+func (c cacheAdmin) Invalidate(orderUID string) error {
+	if err := c.cacheOrder.Invalidate(orderUID); err != nil {
+		return fmt.Errorf("failed to invalidate order: %w", err)
+	}
+
+	c.logger.Infof("order %s invalidated in cache", orderUID)
+
+	return nil
+}
+
+func (c cacheAdmin) EvictPrefix(prefix string) (int, error) {
+	evicted, err := c.cacheOrder.DeletePrefix(prefix)
+	if err != nil {
+		return evicted, fmt.Errorf("failed to evict orders: %w", err)
+	}
+
+	c.logger.Infof("%v orders with prefix %q evicted from cache", evicted, prefix)
+
+	return evicted, nil
+}
+
+func (c cacheAdmin) Flush() error {
+	if err := c.cacheOrder.Flush(); err != nil {
+		return fmt.Errorf("failed to flush cache: %w", err)
+	}
+
+	c.logger.Info("cache flushed")
+
+	return nil
+}
+
+// Reload replaces the cached order with the one stored in the database,
+// an order missing from the database is evicted.
+func (c cacheAdmin) Reload(ctx context.Context, orderUID string) (domain.Order, error) {
+	order, err := findInDatabase(ctx, c.postgresOrder, orderUID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			if _, err := c.Evict(orderUID); err != nil {
+				return domain.Order{}, err
+			}
+		}
+		return domain.Order{}, err
+	}
+
+	if err := c.cacheOrder.Set(order.UID, order); err != nil {
+		return domain.Order{}, fmt.Errorf("failed to set order: %w", err)
+	}
+
+	c.logger.Infof("order %s reloaded to cache", orderUID)
+
+	return order, nil
+}
+
+func (c cacheAdmin) Stats() cache.Stats {
+	return c.cacheOrder.Stats()
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+)
+
+const topBrands = 5
+
+type Customer interface {
+	Summary(ctx context.Context, customerID string) (domain.CustomerSummary, error)
+}
+
+type customer struct {
+	postgresCustomer postgres.Customer
+}
+
+func NewCustomer(postgresCustomer postgres.Customer) Customer {
+	return &customer{
+		postgresCustomer: postgresCustomer,
+	}
+}
+
+func (c customer) Summary(ctx context.Context, customerID string) (domain.CustomerSummary, error) {
+	summary, err := c.postgresCustomer.FindCustomerSummary(ctx, customerID, topBrands)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			return domain.CustomerSummary{}, domain.ErrNothingFound
+		}
+		return domain.CustomerSummary{}, fmt.Errorf("failed to find customer summary: %w", err)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+	appLogger "github.com/Be1chenok/levelZero/logger"
+	"go.uber.org/zap"
+)
+
+type DeadLetter interface {
+	Add(ctx context.Context, deadLetter domain.DeadLetter) (int64, error)
+	FindAll(ctx context.Context, filter domain.DeadLetterFilter) ([]domain.DeadLetter, error)
+	FindByID(ctx context.Context, id int64) (domain.DeadLetter, error)
+	Replay(ctx context.Context, id int64) (domain.Order, domain.Outcome, error)
+}
+
+type deadLetter struct {
+	postgresDeadLetter postgres.DeadLetter
+	order              Order
+	logger             appLogger.Logger
+}
+
+func NewDeadLetter(postgresDeadLetter postgres.DeadLetter, order Order, logger appLogger.Logger) DeadLetter {
+	return &deadLetter{
+		postgresDeadLetter: postgresDeadLetter,
+		order:              order,
+		logger:             logger.With(zap.String("component", "service-dead-letter")),
+	}
+}
+
+func (d deadLetter) Add(ctx context.Context, deadLetter domain.DeadLetter) (int64, error) {
+	id, err := d.postgresDeadLetter.AddDeadLetter(ctx, deadLetter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add dead letter: %w", err)
+	}
+	d.logger.Infof("message %d from %s has been dead-lettered: %d", deadLetter.Sequence, deadLetter.Subject, id)
+
+	return id, nil
+}
+
+func (d deadLetter) FindAll(ctx context.Context, filter domain.DeadLetterFilter) ([]domain.DeadLetter, error) {
+	deadLetters, err := d.postgresDeadLetter.FindDeadLetters(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dead letters: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+func (d deadLetter) FindByID(ctx context.Context, id int64) (domain.DeadLetter, error) {
+	deadLetter, err := d.postgresDeadLetter.FindDeadLetterByID(ctx, id)
+	if err != nil {
+		return domain.DeadLetter{}, fmt.Errorf("failed to find dead letter by id: %w", err)
+	}
+
+	return deadLetter, nil
+}
+
+func (d deadLetter) Replay(ctx context.Context, id int64) (domain.Order, domain.Outcome, error) {
+	deadLetter, err := d.FindByID(ctx, id)
+	if err != nil {
+		return domain.Order{}, "", err
+	}
+
+	if deadLetter.ReplayedAt != nil {
+		return domain.Order{}, "", domain.ErrAlreadyReplayed
+	}
+
+	source := domain.SourceFromContext(ctx)
+	ctx = domain.WithSource(ctx, domain.Source{
+		Channel:   domain.ChannelReplay,
+		Actor:     source.Actor,
+		Reference: strconv.FormatInt(id, 10),
+	})
+
+	order, outcome, err := d.replay(ctx, deadLetter)
+	if err != nil {
+		if e := d.postgresDeadLetter.AddDeadLetterAttempt(ctx, id, err.Error()); e != nil {
+			d.logger.Errorf("failed to record attempt of dead letter %d: %v", id, e)
+		}
+		return domain.Order{}, "", err
+	}
+
+	if err := d.postgresDeadLetter.MarkDeadLetterReplayed(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			return domain.Order{}, "", domain.ErrAlreadyReplayed
+		}
+		return domain.Order{}, "", fmt.Errorf("failed to mark dead letter as replayed: %w", err)
+	}
+	d.logger.Infof("dead letter %d has been replayed: %s", id, order.UID)
+
+	return order, outcome, nil
+}
+
+func (d deadLetter) replay(ctx context.Context, deadLetter domain.DeadLetter) (domain.Order, domain.Outcome, error) {
+	command, err := domain.ParseCommand([]byte(deadLetter.Payload))
+	if err != nil {
+		return domain.Order{}, "", err
+	}
+
+	order, outcome, err := d.order.Execute(ctx, command)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingFound) {
+			return domain.Order{}, "", fmt.Errorf("%w: order %s does not exist", domain.ErrInvalidPayload, command.OrderUID)
+		}
+		return domain.Order{}, "", err
+	}
+	d.logger.Infof("dead letter %d replay outcome: %s", deadLetter.ID, outcome)
+
+	return order, outcome, nil
+}
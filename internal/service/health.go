@@ -0,0 +1,26 @@
+package service
+
+import (
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/cache"
+)
+
+type Health interface {
+	Readiness() domain.WarmUp
+}
+
+type health struct {
+	cacheOrder cache.Order
+}
+
+func NewHealth(cacheOrder cache.Order) Health {
+	return &health{
+		cacheOrder: cacheOrder,
+	}
+}
+
+// Readiness reports the cache warm-up, orders are read through to the
+// database until it is ready.
+func (h health) Readiness() domain.WarmUp {
+	return h.cacheOrder.WarmUpStatus()
+}
@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"time"
 
+	"github.com/Be1chenok/levelZero/internal/config"
 	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/broker"
 	"github.com/Be1chenok/levelZero/internal/repository/cache"
 	"github.com/Be1chenok/levelZero/internal/repository/postgres"
 	appLogger "github.com/Be1chenok/levelZero/logger"
@@ -12,62 +17,398 @@ import (
 )
 
 type Order interface {
+	Execute(ctx context.Context, command domain.Command) (domain.Order, domain.Outcome, error)
+	Create(ctx context.Context, order domain.Order) (domain.Order, domain.Outcome, error)
+	Update(ctx context.Context, order domain.Order, version int64) (domain.Order, domain.Outcome, error)
+	Cancel(ctx context.Context, orderUID string, version int64) (domain.Order, domain.Outcome, error)
+	Delete(ctx context.Context, orderUID string, version int64) error
 	FindByUID(ctx context.Context, orderUID string) (domain.Order, error)
+	FindAll(ctx context.Context, filter domain.OrderFilter) (domain.OrderPage, error)
+	ChangeStatus(ctx context.Context, orderUID string, status domain.OrderStatus, version int64) (domain.StatusChange, error)
+	StatusHistory(ctx context.Context, orderUID string) ([]domain.StatusChange, error)
+	History(ctx context.Context, orderUID string) ([]domain.OrderEvent, error)
+	Track(ctx context.Context, trackNumber string) (domain.Tracking, error)
 }
 
 type order struct {
+	conf          *config.Config
 	postgresOrder postgres.Order
-	cacheOrder    cache.Cache
+	cacheOrder    cache.Order
+	events        broker.Events
 	logger        appLogger.Logger
 }
 
-func NewOrder(postgresOrder postgres.Order, cacheOrder cache.Cache, logger appLogger.Logger) Order {
+func NewOrder(conf *config.Config, postgresOrder postgres.Order, cacheOrder cache.Order, events broker.Events, logger appLogger.Logger) Order {
 	return &order{
+		conf:          conf,
 		postgresOrder: postgresOrder,
 		cacheOrder:    cacheOrder,
+		events:        events,
 		logger:        logger.With(zap.String("component", "service-order")),
 	}
 }
 
-func (o order) FindByUID(ctx context.Context, orderUID string) (domain.Order, error) {
-	cachedOrder, ok := o.cacheOrder.Get(orderUID)
-	if ok {
-		arr, ok := cachedOrder.(domain.Order)
-		if !ok {
-			o.logger.Error("failed to convert data")
+// Execute routes the command of a broker message to the matching method.
+// Commands are redelivered, deleting an order whose row is missing is a no-op.
+func (o order) Execute(ctx context.Context, command domain.Command) (domain.Order, domain.Outcome, error) {
+	switch command.Type {
+	case domain.CommandCreate:
+		return o.Create(ctx, *command.Order)
+	case domain.CommandUpdate:
+		return o.Update(ctx, *command.Order, command.Version)
+	case domain.CommandCancel:
+		return o.Cancel(ctx, command.OrderUID, command.Version)
+	case domain.CommandDelete:
+		if err := o.Delete(ctx, command.OrderUID, command.Version); err != nil {
+			if errors.Is(err, domain.ErrNothingFound) {
+				o.logger.Infof("order is already deleted: %s", command.OrderUID)
+				return domain.Order{}, domain.OutcomeUnchanged, nil
+			}
+			return domain.Order{}, "", err
 		}
-		return arr, nil
+		return domain.Order{}, domain.OutcomeDeleted, nil
+	default:
+		return domain.Order{}, "", fmt.Errorf("%w: unknown command type %s", domain.ErrInvalidPayload, command.Type)
+	}
+}
+
+// Create stores a new order. A re-sent order with the same content is a no-op,
+// an order with the same UID but a different content is either rejected
+// or replaces the stored one according to the configured conflict policy.
+// The status is not taken from the received order, new orders are created
+// and replaced orders keep their status.
+func (o order) Create(ctx context.Context, order domain.Order) (domain.Order, domain.Outcome, error) {
+	if err := order.Validate(); err != nil {
+		return domain.Order{}, "", fmt.Errorf("failed to validate order: %w", err)
+	}
+	order.DateCreated = domain.NormalizeTime(order.DateCreated)
+	order.Status = domain.StatusCreated
+	order.Version = 1
+
+	err := o.postgresOrder.AddOrder(ctx, order, newOrderEvent(ctx, order.UID, domain.EventCreated, nil))
+	if err == nil {
+		o.logger.Infof("order has been added to database: %s", order.UID)
+		o.setCache(order)
+		o.publishChanged(order.UID, domain.OutcomeCreated)
+
+		return order, domain.OutcomeCreated, nil
+	}
+
+	if !errors.Is(err, domain.ErrAlreadyExists) {
+		return domain.Order{}, "", wrapRepositoryError("failed to add order in data base", err)
+	}
+
+	storedOrder, err := findInDatabase(ctx, o.postgresOrder, order.UID)
+	if err != nil {
+		return domain.Order{}, "", err
+	}
+
+	if sameOrder(storedOrder, order) {
+		o.logger.Infof("order is already stored: %s", order.UID)
+		o.setCache(storedOrder)
+
+		return storedOrder, domain.OutcomeUnchanged, nil
+	}
+
+	if domain.ConflictPolicy(o.conf.Order.ConflictPolicy) != domain.ConflictUpdate {
+		return domain.Order{}, "", domain.ErrConflict
 	}
 
+	if order, err = o.update(ctx, storedOrder, order); err != nil {
+		return domain.Order{}, "", err
+	}
+
+	return order, domain.OutcomeUpdated, nil
+}
+
+// Update replaces the order stored at version, the status is kept.
+// Version 0 replaces the order whatever its version. An order with the stored
+// content is unchanged whatever the version, as the update was already applied.
+func (o order) Update(ctx context.Context, order domain.Order, version int64) (domain.Order, domain.Outcome, error) {
+	if err := order.Validate(); err != nil {
+		return domain.Order{}, "", fmt.Errorf("failed to validate order: %w", err)
+	}
+	order.DateCreated = domain.NormalizeTime(order.DateCreated)
+
+	storedOrder, err := findInDatabase(ctx, o.postgresOrder, order.UID)
+	if err != nil {
+		return domain.Order{}, "", err
+	}
+
+	if sameOrder(storedOrder, order) {
+		o.logger.Infof("order is already stored: %s", order.UID)
+		return storedOrder, domain.OutcomeUnchanged, nil
+	}
+
+	if !matchVersion(storedOrder, version) {
+		return domain.Order{}, "", domain.ErrVersionConflict
+	}
+
+	if order, err = o.update(ctx, storedOrder, order); err != nil {
+		return domain.Order{}, "", err
+	}
+
+	return order, domain.OutcomeUpdated, nil
+}
+
+// Cancel cancels the order stored at version, or at any version when it is 0.
+// A cancelled order is unchanged whatever the version.
+func (o order) Cancel(ctx context.Context, orderUID string, version int64) (domain.Order, domain.Outcome, error) {
+	order, err := findInDatabase(ctx, o.postgresOrder, orderUID)
+	if err != nil {
+		return domain.Order{}, "", err
+	}
+
+	if order.Status == domain.StatusCancelled {
+		o.logger.Infof("order is already cancelled: %s", orderUID)
+		return order, domain.OutcomeUnchanged, nil
+	}
+
+	if !matchVersion(order, version) {
+		return domain.Order{}, "", domain.ErrVersionConflict
+	}
+
+	if order, _, err = o.changeStatus(ctx, order, domain.StatusCancelled); err != nil {
+		return domain.Order{}, "", err
+	}
+
+	return order, domain.OutcomeUpdated, nil
+}
+
+// Delete deletes the order stored at version, or at any version when it is 0.
+// The events of the order are kept.
+func (o order) Delete(ctx context.Context, orderUID string, version int64) error {
 	order, err := o.postgresOrder.FindOrderByUID(ctx, orderUID)
 	if err != nil {
-		return domain.Order{}, fmt.Errorf("failed to find order by UID: %w", err)
+		return wrapRepositoryError("failed to find order by UID", err)
+	}
+
+	if !matchVersion(order, version) {
+		return domain.ErrVersionConflict
+	}
+
+	event := newOrderEvent(ctx, orderUID, domain.EventDeleted, nil)
+	if err := o.postgresOrder.DeleteOrder(ctx, orderUID, order.Version, event); err != nil {
+		return wrapRepositoryError("failed to delete order from data base", err)
+	}
+	o.logger.Infof("order has been deleted from database: %s", orderUID)
+
+	if _, err := o.cacheOrder.Delete(orderUID); err != nil {
+		o.logger.Errorf("failed to delete order %s from cache: %v", orderUID, err)
 	}
+	o.publishChanged(orderUID, domain.OutcomeDeleted)
 
-	delivery, err := o.postgresOrder.FindDeliveryByOrderUID(ctx, orderUID)
+	return nil
+}
+
+func (o order) FindByUID(ctx context.Context, orderUID string) (domain.Order, error) {
+	if cachedOrder, ok := o.cacheOrder.Get(orderUID); ok {
+		return cachedOrder, nil
+	}
+
+	order, err := findInDatabase(ctx, o.postgresOrder, orderUID)
 	if err != nil {
-		return domain.Order{}, fmt.Errorf("failed to find delivery by orderUID: %w", err)
+		return domain.Order{}, err
 	}
 
-	payment, err := o.postgresOrder.FindPaymentByOrderUID(ctx, orderUID)
+	o.setCache(order)
+
+	return order, nil
+}
+
+func (o order) FindAll(ctx context.Context, filter domain.OrderFilter) (domain.OrderPage, error) {
+	page, err := o.postgresOrder.FindOrders(ctx, filter)
 	if err != nil {
-		return domain.Order{}, fmt.Errorf("failed to find payment by orderUID: %w", err)
+		return domain.OrderPage{}, wrapRepositoryError("failed to find orders", err)
+	}
+
+	if page.Orders == nil {
+		page.Orders = []domain.Order{}
 	}
 
-	items, err := o.postgresOrder.FindItemsByOrderUID(ctx, orderUID)
+	return page, nil
+}
+
+// ChangeStatus moves the order stored at version, or at any version when it is 0,
+// to status if the state machine allows it. The current status is read
+// from the database, not from the cache.
+func (o order) ChangeStatus(ctx context.Context, orderUID string, status domain.OrderStatus, version int64) (domain.StatusChange, error) {
+	order, err := findInDatabase(ctx, o.postgresOrder, orderUID)
 	if err != nil {
-		return domain.Order{}, fmt.Errorf("failed to find items by orderUID: %w", err)
+		return domain.StatusChange{}, err
+	}
+
+	if !matchVersion(order, version) {
+		return domain.StatusChange{}, domain.ErrVersionConflict
+	}
+
+	_, change, err := o.changeStatus(ctx, order, status)
+
+	return change, err
+}
+
+func (o order) StatusHistory(ctx context.Context, orderUID string) ([]domain.StatusChange, error) {
+	history, err := o.postgresOrder.FindStatusHistory(ctx, orderUID)
+	if err != nil {
+		return nil, wrapRepositoryError("failed to find status history", err)
+	}
+
+	if len(history) == 0 {
+		return nil, domain.ErrNothingFound
+	}
+
+	return history, nil
+}
+
+// History returns the events of the order, including the deleted one.
+func (o order) History(ctx context.Context, orderUID string) ([]domain.OrderEvent, error) {
+	events, err := o.postgresOrder.FindOrderEvents(ctx, orderUID)
+	if err != nil {
+		return nil, wrapRepositoryError("failed to find order events", err)
+	}
+
+	if len(events) == 0 {
+		if _, err := o.postgresOrder.FindOrderByUID(ctx, orderUID); err != nil {
+			return nil, err
+		}
+		return []domain.OrderEvent{}, nil
+	}
+
+	return events, nil
+}
+
+// update replaces the stored order, keeping its status, and moves it to the next version.
+func (o order) update(ctx context.Context, storedOrder, order domain.Order) (domain.Order, error) {
+	order.Status = storedOrder.Status
+	order.Version = storedOrder.Version + 1
+
+	event := newOrderEvent(ctx, order.UID, domain.EventUpdated, domain.DiffOrders(storedOrder, order))
+	if err := o.postgresOrder.UpdateOrder(ctx, order, storedOrder.Version, event); err != nil {
+		return domain.Order{}, wrapRepositoryError("failed to update order in data base", err)
+	}
+	o.logger.Infof("order has been updated in database: %s", order.UID)
+	o.setCache(order)
+	o.publishChanged(order.UID, domain.OutcomeUpdated)
+
+	return order, nil
+}
+
+func (o order) changeStatus(ctx context.Context, order domain.Order, status domain.OrderStatus) (domain.Order, domain.StatusChange, error) {
+	if err := order.Status.Transition(status); err != nil {
+		return domain.Order{}, domain.StatusChange{}, err
+	}
+
+	change := domain.StatusChange{
+		OrderUID:  order.UID,
+		From:      order.Status,
+		To:        status,
+		ChangedAt: time.Now().UTC(),
+	}
+
+	event := newOrderEvent(ctx, order.UID, domain.EventStatusChanged, []domain.FieldChange{{
+		Field: "status",
+		From:  change.From,
+		To:    change.To,
+	}})
+	event.CreatedAt = change.ChangedAt
+
+	if err := o.postgresOrder.ChangeOrderStatus(ctx, change, order.Version, event); err != nil {
+		return domain.Order{}, domain.StatusChange{}, wrapRepositoryError("failed to change order status", err)
+	}
+	o.logger.Infof("order %s status changed from %s to %s", order.UID, change.From, change.To)
+
+	order.Status = status
+	order.Version++
+	o.setCache(order)
+	o.publishChanged(order.UID, domain.OutcomeUpdated)
+
+	return order, change, nil
+}
+
+func findInDatabase(ctx context.Context, postgresOrder postgres.Order, orderUID string) (domain.Order, error) {
+	order, err := postgresOrder.FindOrderByUID(ctx, orderUID)
+	if err != nil {
+		return domain.Order{}, wrapRepositoryError("failed to find order by UID", err)
+	}
+
+	delivery, err := postgresOrder.FindDeliveryByOrderUID(ctx, orderUID)
+	if err != nil {
+		return domain.Order{}, wrapRepositoryError("failed to find delivery by orderUID", err)
+	}
+
+	payment, err := postgresOrder.FindPaymentByOrderUID(ctx, orderUID)
+	if err != nil {
+		return domain.Order{}, wrapRepositoryError("failed to find payment by orderUID", err)
+	}
+
+	items, err := postgresOrder.FindItemsByOrderUID(ctx, orderUID)
+	if err != nil {
+		return domain.Order{}, wrapRepositoryError("failed to find items by orderUID", err)
 	}
 
 	order.Delivery = delivery
 	order.Payment = payment
 	order.Items = items
 
+	return order, nil
+}
+
+func (o order) setCache(order domain.Order) {
 	if err := o.cacheOrder.Set(order.UID, order); err != nil {
 		o.logger.Errorf("failed to add order %s to cache: %v", order.UID, err)
+		return
 	}
 
 	o.logger.Infof("order %s added to cache", order.UID)
+}
 
-	return order, nil
+// newOrderEvent attributes the change to the source of ctx.
+func newOrderEvent(ctx context.Context, orderUID string, eventType domain.OrderEventType, diff []domain.FieldChange) domain.OrderEvent {
+	return domain.OrderEvent{
+		OrderUID:  orderUID,
+		Type:      eventType,
+		Source:    domain.SourceFromContext(ctx),
+		Diff:      diff,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// publishChanged notifies the other instances to drop the order from their caches.
+func (o order) publishChanged(orderUID string, outcome domain.Outcome) {
+	if err := o.events.PublishOrderChanged(domain.OrderChanged{
+		Instance:  o.conf.Stan.ClientID,
+		OrderUID:  orderUID,
+		Outcome:   outcome,
+		ChangedAt: time.Now().UTC(),
+	}); err != nil {
+		o.logger.Errorf("failed to publish order %s change: %v", orderUID, err)
+	}
+}
+
+func matchVersion(order domain.Order, version int64) bool {
+	return version == 0 || order.Version == version
+}
+
+// sameOrder compares orders as they are stored.
+func sameOrder(stored, received domain.Order) bool {
+	if !stored.DateCreated.Equal(received.DateCreated) {
+		return false
+	}
+	stored.DateCreated, received.DateCreated = time.Time{}, time.Time{}
+	stored.Status, received.Status = "", ""
+	stored.Version, received.Version = 0, 0
+
+	if len(stored.Items) == 0 && len(received.Items) == 0 {
+		stored.Items, received.Items = nil, nil
+	}
+
+	return reflect.DeepEqual(stored, received)
+}
+
+func wrapRepositoryError(message string, err error) error {
+	if postgres.IsTransient(err) {
+		return fmt.Errorf("%s: %w: %w", message, domain.ErrTemporary, err)
+	}
+
+	return fmt.Errorf("%s: %w", message, err)
 }
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/config"
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/cache"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+	"go.uber.org/zap"
+)
+
+// fakePostgresOrder keeps orders in memory, errs fails the named methods.
+type fakePostgresOrder struct {
+	postgres.Order
+	orders map[string]domain.Order
+	events []domain.OrderEvent
+	errs   map[string]error
+}
+
+func (p *fakePostgresOrder) fail(method string) error {
+	return p.errs[method]
+}
+
+func (p *fakePostgresOrder) AddOrder(ctx context.Context, order domain.Order, event domain.OrderEvent) error {
+	if err := p.fail("AddOrder"); err != nil {
+		return err
+	}
+	if _, ok := p.orders[order.UID]; ok {
+		return domain.ErrAlreadyExists
+	}
+	p.orders[order.UID] = order
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func (p *fakePostgresOrder) UpdateOrder(ctx context.Context, order domain.Order, version int64, event domain.OrderEvent) error {
+	if err := p.fail("UpdateOrder"); err != nil {
+		return err
+	}
+	if stored, ok := p.orders[order.UID]; !ok || stored.Version != version {
+		return domain.ErrVersionConflict
+	}
+	p.orders[order.UID] = order
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func (p *fakePostgresOrder) DeleteOrder(ctx context.Context, orderUID string, version int64, event domain.OrderEvent) error {
+	if err := p.fail("DeleteOrder"); err != nil {
+		return err
+	}
+	if stored, ok := p.orders[orderUID]; !ok || stored.Version != version {
+		return domain.ErrVersionConflict
+	}
+	delete(p.orders, orderUID)
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func (p *fakePostgresOrder) ChangeOrderStatus(ctx context.Context, change domain.StatusChange, version int64, event domain.OrderEvent) error {
+	if err := p.fail("ChangeOrderStatus"); err != nil {
+		return err
+	}
+	stored, ok := p.orders[change.OrderUID]
+	if !ok || stored.Version != version {
+		return domain.ErrVersionConflict
+	}
+	stored.Status = change.To
+	stored.Version++
+	p.orders[change.OrderUID] = stored
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func (p *fakePostgresOrder) find(method, orderUID string) (domain.Order, error) {
+	if err := p.fail(method); err != nil {
+		return domain.Order{}, err
+	}
+	order, ok := p.orders[orderUID]
+	if !ok {
+		return domain.Order{}, domain.ErrNothingFound
+	}
+
+	return order, nil
+}
+
+func (p *fakePostgresOrder) FindOrderByUID(ctx context.Context, orderUID string) (domain.Order, error) {
+	order, err := p.find("FindOrderByUID", orderUID)
+	order.Delivery, order.Payment, order.Items = domain.Delivery{}, domain.Payment{}, nil
+
+	return order, err
+}
+
+func (p *fakePostgresOrder) FindDeliveryByOrderUID(ctx context.Context, orderUID string) (domain.Delivery, error) {
+	order, err := p.find("FindDeliveryByOrderUID", orderUID)
+	return order.Delivery, err
+}
+
+func (p *fakePostgresOrder) FindPaymentByOrderUID(ctx context.Context, orderUID string) (domain.Payment, error) {
+	order, err := p.find("FindPaymentByOrderUID", orderUID)
+	return order.Payment, err
+}
+
+func (p *fakePostgresOrder) FindItemsByOrderUID(ctx context.Context, orderUID string) ([]domain.Item, error) {
+	order, err := p.find("FindItemsByOrderUID", orderUID)
+	return order.Items, err
+}
+
+type fakeCacheOrder struct {
+	cache.Order
+	orders map[string]domain.Order
+}
+
+func (c *fakeCacheOrder) Get(key string) (domain.Order, bool) {
+	order, ok := c.orders[key]
+	return order, ok
+}
+
+func (c *fakeCacheOrder) Set(key string, value domain.Order) error {
+	c.orders[key] = value
+	return nil
+}
+
+func (c *fakeCacheOrder) Delete(key string) (bool, error) {
+	_, ok := c.orders[key]
+	delete(c.orders, key)
+
+	return ok, nil
+}
+
+type fakeEvents struct {
+	published []domain.OrderChanged
+}
+
+func (e *fakeEvents) PublishOrderChanged(event domain.OrderChanged) error {
+	e.published = append(e.published, event)
+	return nil
+}
+
+type testService struct {
+	order    *order
+	postgres *fakePostgresOrder
+	cache    *fakeCacheOrder
+	events   *fakeEvents
+}
+
+func newTestService(conflictPolicy domain.ConflictPolicy, stored ...domain.Order) testService {
+	s := testService{
+		postgres: &fakePostgresOrder{orders: make(map[string]domain.Order), errs: make(map[string]error)},
+		cache:    &fakeCacheOrder{orders: make(map[string]domain.Order)},
+		events:   &fakeEvents{},
+	}
+	for _, order := range stored {
+		s.postgres.orders[order.UID] = order
+	}
+
+	conf := &config.Config{}
+	conf.Order.ConflictPolicy = string(conflictPolicy)
+	s.order = NewOrder(conf, s.postgres, s.cache, s.events, zap.NewNop().Sugar()).(*order)
+
+	return s
+}
+
+// testOrder returns a valid order as it is stored at version 1.
+func testOrder() domain.Order {
+	return domain.Order{
+		UID:         "b563feb7b2b84b6test",
+		TrackNumber: "WBILMTESTTRACK",
+		Entry:       "WBIL",
+		Delivery: domain.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+		Payment: domain.Payment{
+			Transaction:  "b563feb7b2b84b6test",
+			Currency:     "USD",
+			Provider:     "wbpay",
+			Amount:       1817,
+			PaymentDT:    1637907727,
+			Bank:         "alpha",
+			DeliveryCost: 1500,
+			GoodsTotal:   317,
+		},
+		Items: []domain.Item{{
+			ChrtID:      9934930,
+			TrackNumber: "WBILMTESTTRACK",
+			Price:       453,
+			RID:         "ab4219087a764ae0btest",
+			Name:        "Mascaras",
+			Sale:        30,
+			Size:        "0",
+			TotalPrice:  317,
+			NmID:        2389212,
+			Brand:       "Vivienne Sabo",
+			Status:      202,
+		}},
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		ShardKey:        "9",
+		SmID:            99,
+		DateCreated:     time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		OofShard:        "1",
+		Status:          domain.StatusCreated,
+		Version:         1,
+	}
+}
+
+func TestExecuteDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		stored      bool
+		version     int64
+		errs        map[string]error
+		wantOutcome domain.Outcome
+		wantErr     error
+		wantStored  bool
+	}{
+		{name: "stored", stored: true, version: 1, wantOutcome: domain.OutcomeDeleted},
+		{name: "already deleted", version: 1, wantOutcome: domain.OutcomeUnchanged},
+		{name: "other version", stored: true, version: 2, wantErr: domain.ErrVersionConflict, wantStored: true},
+		{
+			name:       "order lookup fails",
+			stored:     true,
+			version:    1,
+			errs:       map[string]error{"FindOrderByUID": driver.ErrBadConn},
+			wantErr:    domain.ErrTemporary,
+			wantStored: true,
+		},
+		{
+			name:       "delete fails",
+			stored:     true,
+			version:    1,
+			errs:       map[string]error{"DeleteOrder": driver.ErrBadConn},
+			wantErr:    domain.ErrTemporary,
+			wantStored: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored []domain.Order
+			if tt.stored {
+				stored = append(stored, testOrder())
+			}
+			s := newTestService(domain.ConflictReject, stored...)
+			for method, err := range tt.errs {
+				s.postgres.errs[method] = err
+			}
+
+			command := domain.Command{Type: domain.CommandDelete, OrderUID: testOrder().UID, Version: tt.version}
+			_, outcome, err := s.order.Execute(context.Background(), command)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("Execute() outcome = %q, want %q", outcome, tt.wantOutcome)
+			}
+
+			if _, ok := s.postgres.orders[command.OrderUID]; ok != tt.wantStored {
+				t.Errorf("order stored = %v, want %v", ok, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	changed := testOrder()
+	changed.Locale = "ru"
+
+	tests := []struct {
+		name        string
+		policy      domain.ConflictPolicy
+		stored      []domain.Order
+		order       domain.Order
+		wantOutcome domain.Outcome
+		wantErr     error
+		wantVersion int64
+		wantEvents  int
+	}{
+		{name: "new order", policy: domain.ConflictReject, order: testOrder(), wantOutcome: domain.OutcomeCreated, wantVersion: 1, wantEvents: 1},
+		{name: "same order", policy: domain.ConflictReject, stored: []domain.Order{testOrder()}, order: testOrder(), wantOutcome: domain.OutcomeUnchanged, wantVersion: 1},
+		{name: "conflict rejected", policy: domain.ConflictReject, stored: []domain.Order{testOrder()}, order: changed, wantErr: domain.ErrConflict, wantVersion: 1},
+		{name: "conflict updates", policy: domain.ConflictUpdate, stored: []domain.Order{testOrder()}, order: changed, wantOutcome: domain.OutcomeUpdated, wantVersion: 2, wantEvents: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(tt.policy, tt.stored...)
+
+			order := tt.order
+			order.Status, order.Version = "", 0
+			got, outcome, err := s.order.Create(context.Background(), order)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+				if got.Version != tt.wantVersion {
+					t.Errorf("Create() version = %d, want %d", got.Version, tt.wantVersion)
+				}
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("Create() outcome = %q, want %q", outcome, tt.wantOutcome)
+			}
+
+			if stored := s.postgres.orders[order.UID]; stored.Version != tt.wantVersion {
+				t.Errorf("stored version = %d, want %d", stored.Version, tt.wantVersion)
+			}
+			if len(s.postgres.events) != tt.wantEvents || len(s.events.published) != tt.wantEvents {
+				t.Errorf("events = %d, published = %d, want %d", len(s.postgres.events), len(s.events.published), tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	stored := testOrder()
+	stored.Version = 3
+
+	changed := stored
+	changed.Locale = "ru"
+
+	missing := changed
+	missing.UID = "b563feb7b2b84b6missing"
+
+	tests := []struct {
+		name        string
+		order       domain.Order
+		version     int64
+		errs        map[string]error
+		wantOutcome domain.Outcome
+		wantErr     error
+		wantVersion int64
+	}{
+		{name: "at version", order: changed, version: 3, wantOutcome: domain.OutcomeUpdated, wantVersion: 4},
+		{name: "any version", order: changed, version: 0, wantOutcome: domain.OutcomeUpdated, wantVersion: 4},
+		{name: "stale version", order: changed, version: 2, wantErr: domain.ErrVersionConflict, wantVersion: 3},
+		{name: "same order at stale version", order: stored, version: 2, wantOutcome: domain.OutcomeUnchanged, wantVersion: 3},
+		{name: "missing order", order: missing, version: 3, wantErr: domain.ErrNothingFound, wantVersion: 3},
+		{
+			name:        "update fails",
+			order:       changed,
+			version:     3,
+			errs:        map[string]error{"UpdateOrder": driver.ErrBadConn},
+			wantErr:     domain.ErrTemporary,
+			wantVersion: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(domain.ConflictReject, stored)
+			for method, err := range tt.errs {
+				s.postgres.errs[method] = err
+			}
+
+			got, outcome, err := s.order.Update(context.Background(), tt.order, tt.version)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+				if got.Version != tt.wantVersion || got.Status != stored.Status {
+					t.Errorf("Update() version = %d, status = %s, want %d, %s", got.Version, got.Status, tt.wantVersion, stored.Status)
+				}
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("Update() outcome = %q, want %q", outcome, tt.wantOutcome)
+			}
+
+			if version := s.postgres.orders[stored.UID].Version; version != tt.wantVersion {
+				t.Errorf("stored version = %d, want %d", version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	cancelled := testOrder()
+	cancelled.Status, cancelled.Version = domain.StatusCancelled, 2
+
+	tests := []struct {
+		name        string
+		stored      domain.Order
+		version     int64
+		wantOutcome domain.Outcome
+		wantErr     error
+		wantVersion int64
+	}{
+		{name: "at version", stored: testOrder(), version: 1, wantOutcome: domain.OutcomeUpdated, wantVersion: 2},
+		{name: "any version", stored: testOrder(), version: 0, wantOutcome: domain.OutcomeUpdated, wantVersion: 2},
+		{name: "stale version", stored: testOrder(), version: 5, wantErr: domain.ErrVersionConflict, wantVersion: 1},
+		{name: "already cancelled", stored: cancelled, version: 1, wantOutcome: domain.OutcomeUnchanged, wantVersion: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(domain.ConflictReject, tt.stored)
+
+			got, outcome, err := s.order.Cancel(context.Background(), tt.stored.UID, tt.version)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Cancel() error = %v, want %v", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Cancel() error = %v", err)
+				}
+				if got.Status != domain.StatusCancelled || got.Version != tt.wantVersion {
+					t.Errorf("Cancel() status = %s, version = %d, want %s, %d", got.Status, got.Version, domain.StatusCancelled, tt.wantVersion)
+				}
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("Cancel() outcome = %q, want %q", outcome, tt.wantOutcome)
+			}
+
+			if version := s.postgres.orders[tt.stored.UID].Version; version != tt.wantVersion {
+				t.Errorf("stored version = %d, want %d", version, tt.wantVersion)
+			}
+		})
+	}
+}
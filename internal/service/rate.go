@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+	"github.com/Be1chenok/levelZero/internal/repository/postgres"
+	appLogger "github.com/Be1chenok/levelZero/logger"
+	"go.uber.org/zap"
+)
+
+type Rate interface {
+	Import(ctx context.Context, rates []domain.ExchangeRate) (int, error)
+	ImportFile(ctx context.Context, path string) (int, error)
+	FindAll(ctx context.Context, filter domain.RateFilter) ([]domain.ExchangeRate, error)
+	Find(ctx context.Context, currency, base string, at time.Time) (domain.ExchangeRate, error)
+	ConvertOrder(ctx context.Context, order domain.Order, base string) (domain.ConvertedOrder, error)
+	Totals(ctx context.Context, filter domain.OrderFilter, base string) (domain.Totals, error)
+}
+
+type rate struct {
+	postgresRate postgres.Rate
+	logger       appLogger.Logger
+}
+
+func NewRate(postgresRate postgres.Rate, logger appLogger.Logger) Rate {
+	return &rate{
+		postgresRate: postgresRate,
+		logger:       logger.With(zap.String("component", "service-rate")),
+	}
+}
+
+func (r rate) Import(ctx context.Context, rates []domain.ExchangeRate) (int, error) {
+	if err := domain.ValidateRates(rates); err != nil {
+		return 0, err
+	}
+
+	if err := r.postgresRate.AddRates(ctx, rates); err != nil {
+		return 0, fmt.Errorf("failed to add exchange rates: %w", err)
+	}
+	r.logger.Infof("%d exchange rates have been imported", len(rates))
+
+	return len(rates), nil
+}
+
+// ImportFile imports the rates from a .csv or .json file.
+func (r rate) ImportFile(ctx context.Context, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+
+	rates, err := domain.ParseRates(data, strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return r.Import(ctx, rates)
+}
+
+func (r rate) FindAll(ctx context.Context, filter domain.RateFilter) ([]domain.ExchangeRate, error) {
+	rates, err := r.postgresRate.FindRates(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find exchange rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Find returns the rate of currency to base effective at the time, a currency
+// converts to itself at 1.
+func (r rate) Find(ctx context.Context, currency, base string, at time.Time) (domain.ExchangeRate, error) {
+	if currency == base {
+		return domain.ExchangeRate{Currency: currency, Base: base, Rate: domain.NewDecimal(1)}, nil
+	}
+
+	rate, err := r.postgresRate.FindRate(ctx, currency, base, at)
+	if err != nil {
+		if errors.Is(err, domain.ErrRateNotFound) {
+			return domain.ExchangeRate{}, fmt.Errorf("%w: %s to %s at %s", domain.ErrRateNotFound, currency, base, at.Format(time.RFC3339))
+		}
+		return domain.ExchangeRate{}, err
+	}
+
+	return rate, nil
+}
+
+// ConvertOrder converts the payment of the order to base at the rate effective
+// when the order was created.
+func (r rate) ConvertOrder(ctx context.Context, order domain.Order, base string) (domain.ConvertedOrder, error) {
+	rate, err := r.Find(ctx, order.Payment.Currency, base, order.DateCreated)
+	if err != nil {
+		return domain.ConvertedOrder{}, err
+	}
+
+	conversion, err := order.Payment.Convert(rate)
+	if err != nil {
+		return domain.ConvertedOrder{}, fmt.Errorf("failed to convert payment: %w", err)
+	}
+
+	return domain.ConvertedOrder{Order: order, Converted: conversion}, nil
+}
+
+func (r rate) Totals(ctx context.Context, filter domain.OrderFilter, base string) (domain.Totals, error) {
+	sums, err := r.postgresRate.SumOrders(ctx, filter, base)
+	if err != nil {
+		return domain.Totals{}, fmt.Errorf("failed to sum orders: %w", err)
+	}
+
+	totals := domain.Totals{
+		Base:         base,
+		Amount:       domain.NewMoney(0, base),
+		DeliveryCost: domain.NewMoney(0, base),
+		GoodsTotal:   domain.NewMoney(0, base),
+		CustomFee:    domain.NewMoney(0, base),
+	}
+
+	for _, sum := range sums {
+		rate := domain.ExchangeRate{Currency: sum.Currency, Base: base, Rate: domain.NewDecimal(1)}
+		if sum.Currency != base {
+			if sum.Rate == nil {
+				totals.Unconverted = append(totals.Unconverted, domain.NewMoney(sum.Amount, sum.Currency))
+				continue
+			}
+			rate = *sum.Rate
+		}
+
+		if err := addConverted(&totals, sum, rate); err != nil {
+			return domain.Totals{}, fmt.Errorf("failed to convert %s totals: %w", sum.Currency, err)
+		}
+		totals.Orders += sum.Orders
+	}
+
+	return totals, nil
+}
+
+func addConverted(totals *domain.Totals, sum domain.CurrencyTotal, rate domain.ExchangeRate) error {
+	fields := []struct {
+		total  *domain.Money
+		amount int64
+	}{
+		{&totals.Amount, sum.Amount},
+		{&totals.DeliveryCost, sum.DeliveryCost},
+		{&totals.GoodsTotal, sum.GoodsTotal},
+		{&totals.CustomFee, sum.CustomFee},
+	}
+
+	for _, field := range fields {
+		converted, err := domain.NewMoney(field.amount, sum.Currency).Convert(rate)
+		if err != nil {
+			return err
+		}
+
+		if *field.total, err = field.total.Add(converted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
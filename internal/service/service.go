@@ -1,16 +1,31 @@
 package service
 
 import (
+	"github.com/Be1chenok/levelZero/internal/config"
 	"github.com/Be1chenok/levelZero/internal/repository"
 	appLogger "github.com/Be1chenok/levelZero/logger"
 )
 
 type Service struct {
 	Order
+	DeadLetter
+	Health
+	Cache
+	Rate
+	Analytics
+	Customer
 }
 
-func New(repo *repository.Repository, logger appLogger.Logger) *Service {
+func New(conf *config.Config, repo *repository.Repository, logger appLogger.Logger) *Service {
+	order := NewOrder(conf, repo.PostgresOrder, repo.CacheOrder, repo.BrokerEvents, logger)
+
 	return &Service{
-		Order: NewOrder(repo.PostgresOrder, repo.CacheOrder, logger),
+		Order:      order,
+		DeadLetter: NewDeadLetter(repo.PostgresDeadLetter, order, logger),
+		Health:     NewHealth(repo.CacheOrder),
+		Cache:      NewCache(repo.PostgresOrder, repo.CacheOrder, logger),
+		Rate:       NewRate(repo.PostgresRate, logger),
+		Analytics:  NewAnalytics(repo.PostgresAnalytics),
+		Customer:   NewCustomer(repo.PostgresCustomer),
 	}
 }
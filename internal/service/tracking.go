@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Be1chenok/levelZero/internal/domain"
+)
+
+// maxTrackedOrders bounds the orders a track number resolves to.
+const maxTrackedOrders = 100
+
+// Track finds the orders with the track number on the order or on any of its
+// items, it fails with domain.ErrNothingFound when there are none.
+func (o order) Track(ctx context.Context, trackNumber string) (domain.Tracking, error) {
+	page, err := o.FindAll(ctx, domain.OrderFilter{
+		TrackNumber: trackNumber,
+		Sort:        domain.SortByDateCreated,
+		Desc:        true,
+		Limit:       maxTrackedOrders,
+	})
+	if err != nil {
+		return domain.Tracking{}, err
+	}
+
+	if len(page.Orders) == 0 {
+		return domain.Tracking{}, domain.ErrNothingFound
+	}
+
+	return domain.NewTracking(trackNumber, page.Orders), nil
+}
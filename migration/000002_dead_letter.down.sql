@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_dead_letters_pending;
+
+DROP TABLE IF EXISTS dead_letters;
\ No newline at end of file
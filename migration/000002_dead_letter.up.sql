@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS dead_letters(
+    id BIGSERIAL PRIMARY KEY,
+    subject VARCHAR(255) NOT NULL,
+    sequence BIGINT NOT NULL,
+    payload BYTEA NOT NULL,
+    error TEXT NOT NULL,
+    attempts INT NOT NULL,
+    first_failed_at TIMESTAMP NOT NULL,
+    last_failed_at TIMESTAMP NOT NULL,
+    replayed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_pending ON dead_letters (id) WHERE replayed_at IS NULL;
@@ -0,0 +1,7 @@
+CREATE INDEX IF NOT EXISTS idx_orders_date_created ON orders (date_created, uid);
+CREATE INDEX IF NOT EXISTS idx_orders_track_number ON orders (track_number);
+CREATE INDEX IF NOT EXISTS idx_orders_delivery_service ON orders (delivery_service);
+CREATE INDEX IF NOT EXISTS idx_payments_provider ON payments (provider);
+CREATE INDEX IF NOT EXISTS idx_payments_bank ON payments (bank);
+CREATE INDEX IF NOT EXISTS idx_items_brand ON items (brand);
+CREATE INDEX IF NOT EXISTS idx_items_nm_id ON items (nm_id);
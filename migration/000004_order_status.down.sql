@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_order_status_history;
+
+DROP TABLE IF EXISTS order_status_history;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
\ No newline at end of file
@@ -0,0 +1,15 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'created';
+
+CREATE TABLE IF NOT EXISTS order_status_history(
+    id BIGSERIAL PRIMARY KEY,
+    order_uid VARCHAR(64) NOT NULL,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (order_uid) REFERENCES orders (uid) ON DELETE CASCADE
+);
+
+INSERT INTO order_status_history (order_uid, to_status, changed_at)
+SELECT uid, status, date_created FROM orders;
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history ON order_status_history (order_uid, id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS order_events_append_only ON order_events;
+
+DROP FUNCTION IF EXISTS order_events_append_only;
+
+DROP INDEX IF EXISTS idx_order_events;
+
+DROP TABLE IF EXISTS order_events;
\ No newline at end of file
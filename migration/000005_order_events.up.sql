@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS order_events(
+    id BIGSERIAL PRIMARY KEY,
+    order_uid VARCHAR(64) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    reference VARCHAR(255) NOT NULL,
+    diff JSONB,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_events ON order_events (order_uid, id);
+
+CREATE OR REPLACE FUNCTION order_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'order_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_events_append_only
+    BEFORE UPDATE OR DELETE ON order_events
+    FOR EACH ROW EXECUTE FUNCTION order_events_append_only();
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE payments ALTER COLUMN payment_dt TYPE INT;
+ALTER TABLE orders ALTER COLUMN date_created TYPE TIMESTAMP USING date_created AT TIME ZONE 'UTC';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE orders ALTER COLUMN date_created TYPE TIMESTAMPTZ USING date_created AT TIME ZONE 'UTC';
+ALTER TABLE payments ALTER COLUMN payment_dt TYPE BIGINT;
\ No newline at end of file
@@ -0,0 +1,11 @@
+ALTER TABLE items
+    ALTER COLUMN price TYPE INT,
+    ALTER COLUMN total_price TYPE INT;
+
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS payments_currency_check,
+    ALTER COLUMN currency TYPE VARCHAR(6),
+    ALTER COLUMN amount TYPE INT,
+    ALTER COLUMN delivery_cost TYPE INT,
+    ALTER COLUMN goods_total TYPE INT,
+    ALTER COLUMN custom_fee TYPE INT;
\ No newline at end of file
@@ -0,0 +1,23 @@
+UPDATE payments
+    SET currency = upper(trim(currency))
+    WHERE currency <> upper(trim(currency));
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM payments WHERE currency !~ '^[A-Z]{3}$') THEN
+        RAISE EXCEPTION 'payments.currency contains values that are not three-letter currency codes';
+    END IF;
+END
+$$;
+
+ALTER TABLE payments
+    ALTER COLUMN amount TYPE BIGINT,
+    ALTER COLUMN delivery_cost TYPE BIGINT,
+    ALTER COLUMN goods_total TYPE BIGINT,
+    ALTER COLUMN custom_fee TYPE BIGINT,
+    ALTER COLUMN currency TYPE CHAR(3) USING upper(trim(currency)),
+    ADD CONSTRAINT payments_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE items
+    ALTER COLUMN price TYPE BIGINT,
+    ALTER COLUMN total_price TYPE BIGINT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS exchange_rates(
+    currency CHAR(3) NOT NULL,
+    base CHAR(3) NOT NULL,
+    rate NUMERIC(24, 10) NOT NULL CHECK (rate > 0),
+    effective_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (currency, base, effective_at)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_orders_customer_id;
\ No newline at end of file
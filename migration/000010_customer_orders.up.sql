@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders (customer_id, date_created);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_items_track_number;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_items_track_number ON items (track_number);
\ No newline at end of file
@@ -112,11 +112,11 @@ func GenerateOrder(n int) Order {
 			RequestID:    "",
 			Currency:     "USD",
 			Provider:     "wbpay",
-			Amount:       n * 20,
+			Amount:       2134,
 			PaymentDT:    int64(12 * n),
 			Bank:         "alpha",
 			DeliveryCost: 1500,
-			GoodsTotal:   317,
+			GoodsTotal:   634,
 			CustomFee:    0,
 		},
 		Items: []Item{